@@ -0,0 +1,94 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command snapshot generates the NodeSimulators recreating the nodes of a cluster,
+// read from the cluster of --kubeconfig or from a `kubectl get nodes -o yaml` file.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/snapshot"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func main() {
+	var file string
+	var output string
+	var opts snapshot.Options
+	flag.StringVar(&file, "file", "", "YAML or JSON file of the nodes, such as the output of kubectl get nodes -o yaml. Read instead of the cluster when set.")
+	flag.StringVar(&output, "output", "", "File the NodeSimulator manifests are written to. Standard output when empty.")
+	flag.StringVar(&opts.Namespace, "namespace", "default", "Namespace of the NodeSimulators.")
+	flag.StringVar(&opts.NamePrefix, "name-prefix", "snapshot", "Prefix of the NodeSimulator names, followed by the index of the group.")
+	flag.BoolVar(&opts.Anonymize, "anonymize", false,
+		"Replace the keys and values of the labels and taints outside of the kubernetes.io and k8s.io domains by salted hashes.")
+	flag.StringVar(&opts.Salt, "anonymize-salt", "",
+		"Salt of the --anonymize hashes, to get the same hashes across runs. Random when empty.")
+	flag.Parse()
+
+	if err := run(file, output, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "snapshot: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(file, output string, opts snapshot.Options) error {
+	var (
+		nodes []v1.Node
+		err   error
+	)
+	if file != "" {
+		nodes, err = snapshot.ReadNodes(file)
+	} else {
+		nodes, err = listNodes()
+	}
+	if err != nil {
+		return err
+	}
+
+	nodeSims, err := snapshot.Generate(nodes, opts)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return snapshot.Write(w, nodeSims)
+}
+
+// listNodes lists the nodes of the cluster of the --kubeconfig flag, or of the
+// default kubeconfig.
+func listNodes() ([]v1.Node, error) {
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.ListNodes(clientSet)
+}
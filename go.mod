@@ -5,9 +5,13 @@ go 1.13
 require (
 	github.com/NJUPT-ISL/SCV v0.0.0-20200908005541-d990930d5755
 	github.com/go-logr/logr v0.1.0
+	github.com/golang/protobuf v1.3.2
+	github.com/prometheus/client_golang v0.9.2
+	google.golang.org/grpc v1.23.0
 	k8s.io/api v0.0.0-20190918155943-95b840bb6a1f
 	k8s.io/apimachinery v0.0.0-20190913080033-27d36303b655
 	k8s.io/client-go v0.0.0-20190918160344-1fbdaa4c8d90
 	k8s.io/klog v0.4.0
 	sigs.k8s.io/controller-runtime v0.4.0
+	sigs.k8s.io/yaml v1.1.0
 )
@@ -17,14 +17,31 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/autoscaler"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/config"
 	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/pod"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/kubelet"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/metrics"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/metricsapi"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/replay"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/report"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/shard"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/usage"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 	"os"
+	"strings"
+	"time"
 
 	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	simv1beta2 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1beta2"
 	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	scvv1 "github.com/NJUPT-ISL/SCV/api/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -41,29 +58,160 @@ var (
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
 	_ = simv1.AddToScheme(scheme)
+	_ = simv1beta2.AddToScheme(scheme)
+	_ = scvv1.AddToScheme(scheme)
 	// +kubebuilder:scaffold:scheme
 }
 
 func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
+	var podShutdownSeconds int64
+	var eventQPS float64
+	var eventBurst int
+	var metricsAPIAddr string
+	var metricsAPICertDir string
+	var usageOptions usage.Options
+	var kubeletPort int
+	var kubeletCertDir string
+	var enableSharding bool
+	var shardIdentity string
+	var shardNamespace string
+	var shardLeaseDuration time.Duration
+	var configFile string
+	var clientQPS float64
+	var namespaces string
+	var replayTrace string
+	var replayFormat string
+	var replayOptions replay.Replayer
+	var replayShardIdentity string
+	var reportAddr string
+	var autoscalerAddr string
+	var autoscalerCertDir string
+	var enableWebhooks bool
+	var webhookCertDir string
+	simConfig := config.Default()
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	flag.Int64Var(&podShutdownSeconds, "pod-shutdown-seconds", -1,
+		"Simulated time containers take to stop after their pod is deleted, capped by the grace period. A negative value waits for the whole grace period.")
+	flag.Float64Var(&eventQPS, "event-qps", 50, "Maximum number of events per second the simulator records in total.")
+	flag.IntVar(&eventBurst, "event-burst", 100, "Maximum burst of events the simulator records in total.")
+	flag.StringVar(&metricsAPIAddr, "metrics-api-addr", "",
+		"The address the metrics.k8s.io API for fake nodes and pods binds to. Empty disables it.")
+	flag.StringVar(&metricsAPICertDir, "metrics-api-cert-dir", "",
+		"Directory holding tls.crt and tls.key of the metrics.k8s.io API. A self-signed certificate is used when empty.")
+	flag.StringVar(&usageOptions.Model, "usage-model", "fraction", "Simulated resource usage model of the pods: fraction, sine or trace.")
+	flag.Float64Var(&usageOptions.Fraction, "usage-fraction", 0.5, "Fraction of the requests used by the fraction model.")
+	flag.Float64Var(&usageOptions.Min, "usage-min", 0.1, "Lowest fraction of the requests used by the sine model.")
+	flag.Float64Var(&usageOptions.Max, "usage-max", 0.9, "Highest fraction of the requests used by the sine model.")
+	flag.DurationVar(&usageOptions.Period, "usage-period", 10*time.Minute, "Period of the sine model.")
+	flag.StringVar(&usageOptions.TracePath, "usage-trace", "", "CSV file of seconds,cpuFraction,memoryFraction rows replayed by the trace model.")
+	flag.IntVar(&kubeletPort, "kubelet-port", 0,
+		"The port of the kubelet API served for all fake nodes and advertised in their daemon endpoints, such as 10250. "+
+			"The API is unauthenticated. 0 disables it.")
+	flag.StringVar(&kubeletCertDir, "kubelet-cert-dir", "",
+		"Directory holding tls.crt and tls.key of the kubelet API. A self-signed certificate is used when empty.")
+	flag.BoolVar(&enableSharding, "enable-sharding", false,
+		"Split the fake nodes between the replicas of the simulator by consistent hashing of their names. Cannot be combined with leader election.")
+	flag.StringVar(&shardIdentity, "shard-identity", "",
+		"Identity of the replica in the shard ring, unique among the replicas. The hostname is used when empty.")
+	flag.StringVar(&shardNamespace, "shard-namespace", "kube-system", "Namespace of the Leases of the replicas.")
+	flag.DurationVar(&shardLeaseDuration, "shard-lease-duration", 30*time.Second,
+		"Time after which a replica that stopped renewing its Lease leaves the shard ring.")
+	flag.StringVar(&configFile, "config", "",
+		"The "+config.Kind+" file, "+config.APIVersion+". Flags set on the command line override its values.")
+	flag.Float64Var(&clientQPS, "client-qps", float64(simConfig.ClientConnection.QPS), "QPS of the API server client.")
+	flag.IntVar(&simConfig.ClientConnection.Burst, "client-burst", simConfig.ClientConnection.Burst, "Burst of the API server client.")
+	flag.IntVar(&simConfig.NodeSyncWorkers, "node-sync-workers", simConfig.NodeSyncWorkers,
+		"Number of fake nodes of a NodeSimulator synced in parallel.")
+	flag.IntVar(&simConfig.HeartbeatWorkers, "heartbeat-workers", simConfig.HeartbeatWorkers, "Number of fake nodes heartbeated in parallel.")
+	flag.IntVar(&simConfig.PodWorkers, "pod-workers", simConfig.PodWorkers, "Number of pods whose status is synced in parallel.")
+	flag.StringVar(&namespaces, "namespaces", "",
+		"Comma separated namespaces whose NodeSimulators and pods are handled. All namespaces when empty.")
+	flag.StringVar(&simConfig.ManagedPodSelector, "managed-pod-selector", simConfig.ManagedPodSelector,
+		"Label selector of the pods run on the fake nodes.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"Serve the conversion webhook of the NodeSimulator API versions on port 9443.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "",
+		"Directory holding tls.crt and tls.key of the webhook server. /tmp/k8s-webhook-server/serving-certs when empty.")
+	flag.StringVar(&replayTrace, "replay-trace", "",
+		"CSV or JSON trace of tasks replayed as managed pods, none when empty.")
+	flag.StringVar(&replayFormat, "replay-format", "generic", "Format of the replayed trace: generic, alibaba, borg or azure.")
+	flag.Float64Var(&replayOptions.SpeedUp, "replay-speedup", 1, "Factor the submit times and durations of the replayed trace are divided by.")
+	flag.StringVar(&replayOptions.Namespace, "replay-namespace", "default", "Namespace of the replayed pods.")
+	flag.StringVar(&replayOptions.NamePrefix, "replay-name-prefix", replay.DefaultNamePrefix, "Name prefix of the replayed pods.")
+	flag.StringVar(&replayOptions.SchedulerName, "replay-scheduler-name", "", "Scheduler of the replayed pods, the default scheduler when empty.")
+	flag.StringVar(&replayShardIdentity, "replay-shard-identity", "",
+		"With sharding, the shard identity of the only replica replaying the trace.")
+	flag.StringVar(&replayOptions.ReportPath, "replay-report", "",
+		"CSV file the creation, scheduling, start and completion times of the replayed pods are written to.")
+	flag.StringVar(&reportAddr, "report-addr", "",
+		"The address the scheduling report of the managed pods is served on, e.g. :8081. Disabled when empty.")
+	flag.StringVar(&autoscalerAddr, "autoscaler-grpc-addr", "",
+		"The address the cluster-autoscaler externalgrpc cloud provider is served on, e.g. :8086. Disabled when empty.")
+	flag.StringVar(&autoscalerCertDir, "autoscaler-grpc-cert-dir", "",
+		"The directory of the tls.crt and tls.key of the cloud provider, served in cleartext when empty.")
 	flag.Parse()
 
 	ctrl.SetLogger(zap.New(func(o *zap.Options) {
 		o.Development = true
 	}))
 
+	simConfig.ClientConnection.QPS = float32(clientQPS)
+	if namespaces != "" {
+		simConfig.Namespaces = strings.Split(namespaces, ",")
+	}
+	if configFile != "" {
+		fileConfig, err := config.Load(configFile)
+		if err != nil {
+			setupLog.Error(err, "unable to load config file")
+			os.Exit(1)
+		}
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "client-qps":
+				fileConfig.ClientConnection.QPS = simConfig.ClientConnection.QPS
+			case "client-burst":
+				fileConfig.ClientConnection.Burst = simConfig.ClientConnection.Burst
+			case "node-sync-workers":
+				fileConfig.NodeSyncWorkers = simConfig.NodeSyncWorkers
+			case "heartbeat-workers":
+				fileConfig.HeartbeatWorkers = simConfig.HeartbeatWorkers
+			case "pod-workers":
+				fileConfig.PodWorkers = simConfig.PodWorkers
+			case "namespaces":
+				fileConfig.Namespaces = simConfig.Namespaces
+			case "managed-pod-selector":
+				fileConfig.ManagedPodSelector = simConfig.ManagedPodSelector
+			}
+		})
+		simConfig = fileConfig
+	}
+	if err := simConfig.Validate(); err != nil {
+		setupLog.Error(err, "invalid configuration")
+		os.Exit(1)
+	}
+	setupLog.Info("configuration", "qps", simConfig.ClientConnection.QPS, "burst", simConfig.ClientConnection.Burst,
+		"nodeSyncWorkers", simConfig.NodeSyncWorkers, "heartbeatWorkers", simConfig.HeartbeatWorkers,
+		"podWorkers", simConfig.PodWorkers, "namespaces", simConfig.Namespaces, "managedPodSelector", simConfig.ManagedPodSelector)
+	node.ManagedPodSelector, _ = labels.Parse(simConfig.ManagedPodSelector)
+
 	mgrConfig := ctrl.GetConfigOrDie()
-	mgrConfig.QPS = 1000
-	mgrConfig.Burst = 1000
+	mgrConfig.QPS = simConfig.ClientConnection.QPS
+	mgrConfig.Burst = simConfig.ClientConnection.Burst
+	mgrConfig.WrapTransport = metrics.InstrumentTransport
 	mgr, err := ctrl.NewManager(mgrConfig, ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
 		LeaderElection:     enableLeaderElection,
 		Port:               9443,
+		CertDir:            webhookCertDir,
+		EventBroadcaster: record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{
+			QPS:       float32(eventQPS),
+			BurstSize: eventBurst,
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -77,33 +225,193 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err = (&node.SimReconciler{
-		Client:    mgr.GetClient(),
-		ClientSet: clientSet,
-		Log:       ctrl.Log.WithName("controllers").WithName("NodeSimulator"),
-		Scheme:    mgr.GetScheme(),
-	}).SetupWithManager(mgr); err != nil {
+	recorder := util.NewRateLimitedRecorder(mgr.GetEventRecorderFor("kubelet"), float32(eventQPS), eventBurst)
+
+	usageModel, err := usageOptions.NewModel()
+	if err != nil {
+		setupLog.Error(err, "unable to create usage model")
+		os.Exit(1)
+	}
+	usageProvider := usage.NewProfileProvider(mgr.GetClient(), usageModel)
+
+	var sharder *shard.Sharder
+	if enableSharding {
+		if enableLeaderElection {
+			setupLog.Error(nil, "sharding cannot be combined with leader election")
+			os.Exit(1)
+		}
+		if shardIdentity == "" {
+			if shardIdentity, err = os.Hostname(); err != nil {
+				setupLog.Error(err, "unable to get hostname for the shard identity")
+				os.Exit(1)
+			}
+		}
+		sharder = &shard.Sharder{
+			Client:        mgr.GetClient(),
+			Identity:      shardIdentity,
+			Namespace:     shardNamespace,
+			LeaseDuration: shardLeaseDuration,
+			RenewPeriod:   shardLeaseDuration / 3,
+		}
+		if err := mgr.Add(sharder); err != nil {
+			setupLog.Error(err, "unable to add sharder")
+			os.Exit(1)
+		}
+	}
+
+	nodeSimReconciler := &node.SimReconciler{
+		Client:      mgr.GetClient(),
+		ClientSet:   clientSet,
+		Log:         ctrl.Log.WithName("controllers").WithName("NodeSimulator"),
+		Scheme:      mgr.GetScheme(),
+		Recorder:    recorder,
+		KubeletPort: int32(kubeletPort),
+		Shard:       sharder,
+		Namespaces:  simConfig.Namespaces,
+		Workers:     simConfig.NodeSyncWorkers,
+	}
+	if err = nodeSimReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "NodeSimulator")
 		os.Exit(1)
 	}
 
+	if err = (&node.ClusterSimReconciler{
+		SimReconciler: nodeSimReconciler,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterNodeSimulator")
+		os.Exit(1)
+	}
+
+	if enableWebhooks {
+		if err = ctrl.NewWebhookManagedBy(mgr).For(&simv1.NodeSimulator{}).Complete(); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "NodeSimulator")
+			os.Exit(1)
+		}
+	}
+
+	if err = (&node.AttachReconciler{
+		Client: mgr.GetClient(),
+		Shard:  sharder,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "VolumeAttacher")
+		os.Exit(1)
+	}
+
+	var podShutdownPeriod *time.Duration
+	if podShutdownSeconds >= 0 {
+		period := time.Duration(podShutdownSeconds) * time.Second
+		podShutdownPeriod = &period
+	}
+
+	var reportRecorder *report.Recorder
+	if reportAddr != "" {
+		reportRecorder = report.NewRecorder()
+	}
+
 	if err = (&pod.SimReconciler{
-		Client:    mgr.GetClient(),
-		ClientSet: clientSet,
-		Scheme:    mgr.GetScheme(),
+		Client:         mgr.GetClient(),
+		ClientSet:      clientSet,
+		Scheme:         mgr.GetScheme(),
+		Recorder:       recorder,
+		Usage:          usageProvider,
+		ShutdownPeriod: podShutdownPeriod,
+		Shard:          sharder,
+		Namespaces:     simConfig.Namespaces,
+		Workers:        simConfig.PodWorkers,
+		Report:         reportRecorder,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "PodSimulator")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 
+	if metricsAPIAddr != "" {
+		if err := mgr.Add(&metricsapi.Server{
+			Client:  mgr.GetClient(),
+			Usage:   usageProvider,
+			Addr:    metricsAPIAddr,
+			CertDir: metricsAPICertDir,
+			Window:  30 * time.Second,
+		}); err != nil {
+			setupLog.Error(err, "unable to add metrics API server")
+			os.Exit(1)
+		}
+	}
+
+	if kubeletPort != 0 {
+		if err := kubelet.IndexNodeAddresses(mgr.GetFieldIndexer()); err != nil {
+			setupLog.Error(err, "unable to index node addresses")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&kubelet.Server{
+			Client:  mgr.GetClient(),
+			Usage:   usageProvider,
+			Addr:    fmt.Sprintf(":%d", kubeletPort),
+			CertDir: kubeletCertDir,
+		}); err != nil {
+			setupLog.Error(err, "unable to add kubelet server")
+			os.Exit(1)
+		}
+	}
+
+	if autoscalerAddr != "" {
+		if err := mgr.Add(&autoscaler.Server{
+			Provider: &autoscaler.Provider{Nodes: nodeSimReconciler, APIReader: mgr.GetAPIReader()},
+			Addr:     autoscalerAddr,
+			CertDir:  autoscalerCertDir,
+		}); err != nil {
+			setupLog.Error(err, "unable to add cluster autoscaler cloud provider")
+			os.Exit(1)
+		}
+	}
+
+	if reportAddr != "" {
+		if err := mgr.Add(&report.Server{
+			Client:   mgr.GetClient(),
+			Recorder: reportRecorder,
+			Addr:     reportAddr,
+		}); err != nil {
+			setupLog.Error(err, "unable to add report server")
+			os.Exit(1)
+		}
+	}
+
+	if replayTrace != "" && enableSharding && replayShardIdentity == "" {
+		setupLog.Error(nil, "replaying a trace with sharding needs --replay-shard-identity")
+		os.Exit(1)
+	}
+	if replayTrace != "" && (!enableSharding || replayShardIdentity == shardIdentity) {
+		format, ok := replay.Formats[replayFormat]
+		if !ok {
+			setupLog.Error(fmt.Errorf("unknown trace format %q", replayFormat), "unable to read replay trace")
+			os.Exit(1)
+		}
+		tasks, err := replay.ReadTrace(replayTrace, format)
+		if err != nil {
+			setupLog.Error(err, "unable to read replay trace")
+			os.Exit(1)
+		}
+		replayOptions.Labels, err = node.ManagedPodLabels()
+		if err != nil {
+			setupLog.Error(err, "unable to label replayed pods")
+			os.Exit(1)
+		}
+		replayOptions.Client = mgr.GetClient()
+		replayOptions.Tasks = tasks
+		if err := mgr.Add(&replayOptions); err != nil {
+			setupLog.Error(err, "unable to add replayer")
+			os.Exit(1)
+		}
+	}
+
 	stopChan := make(chan struct{}, 0)
-	nodeUpdater, err := node.NewNodeUpdater(mgr.GetClient(), clientSet,
-		workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	nodeUpdater, err := node.NewNodeUpdater(mgr.GetClient(), clientSet, recorder, usageProvider,
+		workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "node-updater"),
 		stopChan)
 
 	if err == nil {
-		go nodeUpdater.Run(5, stopChan)
+		nodeUpdater.Shard = sharder
+		go nodeUpdater.Run(simConfig.HeartbeatWorkers, stopChan)
 	} else {
 		klog.Errorf("New NodeUpdate Error: %v", err)
 	}
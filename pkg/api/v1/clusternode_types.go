@@ -0,0 +1,69 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterNodeSimulatorKind is the kind of the ClusterNodeSimulator objects.
+const ClusterNodeSimulatorKind = "ClusterNodeSimulator"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.status.desiredNodes`
+// +kubebuilder:printcolumn:name="Created",type=integer,JSONPath=`.status.createdNodes`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterNodeSimulator is the cluster-scoped sibling of NodeSimulator, its nodes
+// are named after it without a namespace prefix.
+type ClusterNodeSimulator struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeSimulatorSpec   `json:"spec,omitempty"`
+	Status NodeSimulatorStatus `json:"status,omitempty"`
+}
+
+// NodeSimulator returns a copy of the ClusterNodeSimulator as a NodeSimulator with
+// an empty namespace. It keeps the kind of the ClusterNodeSimulator, so that the
+// events recorded for it refer to the ClusterNodeSimulator.
+func (in *ClusterNodeSimulator) NodeSimulator() *NodeSimulator {
+	return &NodeSimulator{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: GroupVersion.String(),
+			Kind:       ClusterNodeSimulatorKind,
+		},
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Spec:       *in.Spec.DeepCopy(),
+		Status:     *in.Status.DeepCopy(),
+	}
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterNodeSimulatorList contains a list of ClusterNodeSimulator
+type ClusterNodeSimulatorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterNodeSimulator `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterNodeSimulator{}, &ClusterNodeSimulatorList{})
+}
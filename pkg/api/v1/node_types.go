@@ -17,6 +17,7 @@ package v1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -30,14 +31,162 @@ type NodeSimulatorSpec struct {
 	Taints    []v1.Taint       `json:"taints,omitempty" protobuf:"bytes,5,opt,name=taints"`
 	Addresses []v1.NodeAddress `json:"addresses,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,5,rep,name=addresses"`
 	Capacity  v1.ResourceList  `json:"capacity,omitempty" protobuf:"bytes,1,rep,name=capacity,casttype=ResourceList,castkey=ResourceName"`
+	// ScaleDown decides which nodes are removed when Number shrinks.
+	// +optional
+	ScaleDown *ScaleDownSpec `json:"scaleDown,omitempty"`
+	// DriftPolicy decides what happens to the fields of the fake nodes changed by
+	// others: Heal restores them from the spec, Adopt keeps them until the spec
+	// changes. Deleted nodes and leases are recreated either way. Defaults to Heal.
+	// +optional
+	// +kubebuilder:validation:Enum=Heal;Adopt
+	DriftPolicy DriftPolicy `json:"driftPolicy,omitempty"`
+	// Devices are advertised on every node by simulated device plugins.
+	// +optional
+	Devices []DeviceSpec `json:"devices,omitempty"`
+	// CSIDrivers are installed on every node.
+	// +optional
+	CSIDrivers []CSIDriverSpec `json:"csiDrivers,omitempty"`
+	// NodeInfo overrides the system info reported by the nodes.
+	// +optional
+	NodeInfo *NodeInfoSpec `json:"nodeInfo,omitempty"`
+}
+
+// NodeInfoSpec is the system info reported by the fake nodes, the fields left
+// empty keep the defaults of the simulator.
+type NodeInfoSpec struct {
+	// +optional
+	OperatingSystem string `json:"operatingSystem,omitempty"`
+	// +optional
+	Architecture string `json:"architecture,omitempty"`
+	// +optional
+	OSImage string `json:"osImage,omitempty"`
+	// +optional
+	KernelVersion string `json:"kernelVersion,omitempty"`
+	// +optional
+	KubeletVersion string `json:"kubeletVersion,omitempty"`
+	// +optional
+	KubeProxyVersion string `json:"kubeProxyVersion,omitempty"`
+	// +optional
+	ContainerRuntimeVersion string `json:"containerRuntimeVersion,omitempty"`
+}
+
+// CSIDriverSpec describes a CSI driver installed on the fake nodes.
+type CSIDriverSpec struct {
+	// Name of the driver, such as ebs.csi.aws.com.
+	Name string `json:"name"`
+	// NodeID is the ID of the node in the driver, $(NODE_NAME) is replaced by the
+	// name of the node. The node name is used when unset.
+	// +optional
+	NodeID string `json:"nodeID,omitempty"`
+	// TopologyKeys are the node labels the driver uses for topology.
+	// +optional
+	TopologyKeys []string `json:"topologyKeys,omitempty"`
+	// MaxVolumes is the number of volumes of the driver a node can attach, unlimited when unset.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxVolumes *int64 `json:"maxVolumes,omitempty"`
+}
+
+// DriftPolicy decides how the changes made by others to the fake nodes are handled.
+type DriftPolicy string
+
+const (
+	// DriftPolicyHeal restores the changed fields from the spec.
+	DriftPolicyHeal DriftPolicy = "Heal"
+	// DriftPolicyAdopt keeps the changed fields until the spec changes.
+	DriftPolicyAdopt DriftPolicy = "Adopt"
+)
+
+// DeviceSpec describes the devices of one simulated device plugin.
+type DeviceSpec struct {
+	// ResourceName is the extended resource advertised by the plugin, such as nvidia.com/gpu.
+	ResourceName v1.ResourceName `json:"resourceName"`
+	// Count is the number of devices per node.
+	// +kubebuilder:validation:Minimum=0
+	Count int `json:"count"`
+	// Model of the devices, such as Tesla-V100.
+	// +optional
+	Model string `json:"model,omitempty"`
+	// Memory of each device.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+	// DRADriver publishes the devices in ResourceSlices of this Dynamic Resource
+	// Allocation driver instead of as the ResourceName extended resource.
+	// +optional
+	DRADriver string `json:"draDriver,omitempty"`
+	// SCV publishes the devices as GPU cards in the Scv object of every node, for
+	// schedulers reading the SCV inventory.
+	// +optional
+	SCV bool `json:"scv,omitempty"`
+	// Core is the core clock of the cards in MHz.
+	// +optional
+	Core uint `json:"core,omitempty"`
+	// Clock is the memory clock of the cards in MHz.
+	// +optional
+	Clock uint `json:"clock,omitempty"`
+	// Bandwidth is the PCIe bandwidth of the cards in MB/s.
+	// +optional
+	Bandwidth uint `json:"bandwidth,omitempty"`
+	// Power is the power limit of the cards in W.
+	// +optional
+	Power uint `json:"power,omitempty"`
+}
+
+// ScaleDownPolicy selects the nodes to remove when Number shrinks.
+type ScaleDownPolicy string
+
+const (
+	// ScaleDownHighestIndex removes the nodes with the highest name index first.
+	ScaleDownHighestIndex ScaleDownPolicy = "HighestIndex"
+	// ScaleDownFewestPods removes the nodes running the fewest managed pods first.
+	ScaleDownFewestPods ScaleDownPolicy = "FewestPods"
+	// ScaleDownOldest removes the nodes with the oldest creationTimestamp first.
+	ScaleDownOldest ScaleDownPolicy = "Oldest"
+	// ScaleDownExplicit removes the nodes listed in NodeNames first.
+	ScaleDownExplicit ScaleDownPolicy = "Explicit"
+)
+
+// ScaleDownSpec describes how nodes are chosen and drained on scale-down.
+type ScaleDownSpec struct {
+	// Policy defaults to HighestIndex.
+	// +optional
+	// +kubebuilder:validation:Enum=HighestIndex;FewestPods;Oldest;Explicit
+	Policy ScaleDownPolicy `json:"policy,omitempty"`
+	// NodeNames lists the nodes to remove for the Explicit policy.
+	// +optional
+	NodeNames []string `json:"nodeNames,omitempty"`
+	// Drain cordons the removed nodes and evicts their managed pods before deleting them.
+	// +optional
+	Drain bool `json:"drain,omitempty"`
+	// GracePeriodSeconds is passed to the pod evictions, the pod's own value is used when unset.
+	// +optional
+	GracePeriodSeconds *int64 `json:"gracePeriodSeconds,omitempty"`
+	// DrainTimeoutSeconds bounds how long a drain may take before the node is deleted anyway.
+	// +optional
+	DrainTimeoutSeconds *int64 `json:"drainTimeoutSeconds,omitempty"`
 }
 
 // NodeSimulatorStatus defines the observed state of NodeSimulator
 type NodeSimulatorStatus struct {
 	Phase string `json:"phase,omitempty"`
+	// DesiredNodes is the number of fake nodes requested by the spec.
+	// +optional
+	DesiredNodes int `json:"desiredNodes,omitempty"`
+	// CreatedNodes is the number of fake nodes created so far.
+	// +optional
+	CreatedNodes int `json:"createdNodes,omitempty"`
+	// Progress summarizes the creation of the fake nodes, such as "created 3200/10000".
+	// +optional
+	Progress string `json:"progress,omitempty"`
 }
 
 // +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.status.desiredNodes`
+// +kubebuilder:printcolumn:name="Created",type=integer,JSONPath=`.status.createdNodes`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // NodeSimulator is the Schema for the nodesimulators API
 type NodeSimulator struct {
@@ -0,0 +1,104 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// UsageCurveType is the shape of a simulated usage curve.
+type UsageCurveType string
+
+const (
+	UsageConstant   UsageCurveType = "Constant"
+	UsageLinearRamp UsageCurveType = "LinearRamp"
+	UsageSine       UsageCurveType = "Sine"
+	UsageRandomWalk UsageCurveType = "RandomWalk"
+	UsageStep       UsageCurveType = "Step"
+)
+
+// UsageCurve describes the usage of one resource over the lifetime of a pod, in
+// percent of the container requests (or limits when there are no requests).
+type UsageCurve struct {
+	// +kubebuilder:validation:Enum=Constant;LinearRamp;Sine;RandomWalk;Step
+	Type UsageCurveType `json:"type"`
+	// Percent is the usage of Constant, and the start of RandomWalk.
+	// +optional
+	Percent int32 `json:"percent,omitempty"`
+	// FromPercent and ToPercent bound LinearRamp.
+	// +optional
+	FromPercent int32 `json:"fromPercent,omitempty"`
+	// +optional
+	ToPercent int32 `json:"toPercent,omitempty"`
+	// MinPercent and MaxPercent bound Sine and RandomWalk.
+	// +optional
+	MinPercent int32 `json:"minPercent,omitempty"`
+	// MaxPercent is 100 when unset.
+	// +optional
+	MaxPercent int32 `json:"maxPercent,omitempty"`
+	// StepPercent is the largest move of RandomWalk per interval.
+	// +optional
+	StepPercent int32 `json:"stepPercent,omitempty"`
+	// DurationSeconds is how long LinearRamp takes to go from FromPercent to ToPercent.
+	// +optional
+	DurationSeconds int64 `json:"durationSeconds,omitempty"`
+	// PeriodSeconds is the period of Sine, a day when unset.
+	// +optional
+	PeriodSeconds int64 `json:"periodSeconds,omitempty"`
+	// IntervalSeconds is how often RandomWalk moves.
+	// +optional
+	IntervalSeconds int64 `json:"intervalSeconds,omitempty"`
+	// Steps of Step, sorted by AfterSeconds.
+	// +optional
+	Steps []UsageCurveStep `json:"steps,omitempty"`
+}
+
+// UsageCurveStep sets the usage from AfterSeconds after the pod started on.
+type UsageCurveStep struct {
+	AfterSeconds int64 `json:"afterSeconds"`
+	Percent      int32 `json:"percent"`
+}
+
+// UsageProfileSpec defines the simulated usage of the pods referencing the profile
+type UsageProfileSpec struct {
+	// +optional
+	CPU *UsageCurve `json:"cpu,omitempty"`
+	// +optional
+	Memory *UsageCurve `json:"memory,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UsageProfile is the Schema for the usageprofiles API
+type UsageProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec UsageProfileSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UsageProfileList contains a list of UsageProfile
+type UsageProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []UsageProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&UsageProfile{}, &UsageProfileList{})
+}
@@ -0,0 +1,91 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"fmt"
+	"math"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// ConvertTo converts this NodeSimulator to the Hub version (v1).
+func (src *NodeSimulator) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*simv1.NodeSimulator)
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	spec := src.Spec.DeepCopy()
+	dst.Spec = simv1.NodeSimulatorSpec{
+		Number:      int(spec.Replicas),
+		PodCIDRs:    spec.Template.Network.PodCIDRs,
+		Taints:      spec.Template.Taints,
+		Addresses:   spec.Template.Network.Addresses,
+		Capacity:    spec.Template.Capacity,
+		ScaleDown:   spec.ScaleDown,
+		DriftPolicy: spec.DriftPolicy,
+		Devices:     spec.Template.Devices,
+		CSIDrivers:  spec.Template.CSIDrivers,
+		NodeInfo:    spec.Template.NodeInfo,
+	}
+	dst.Status = simv1.NodeSimulatorStatus{
+		Phase:        src.Status.Phase,
+		DesiredNodes: int(src.Status.DesiredReplicas),
+		CreatedNodes: int(src.Status.Replicas),
+		Progress:     src.Status.Progress,
+	}
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1) to this version.
+func (dst *NodeSimulator) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*simv1.NodeSimulator)
+	for name, value := range map[string]int{
+		"spec.number":         src.Spec.Number,
+		"status.desiredNodes": src.Status.DesiredNodes,
+		"status.createdNodes": src.Status.CreatedNodes,
+	} {
+		if value > math.MaxInt32 || value < math.MinInt32 {
+			return fmt.Errorf("NodeSimulator %v/%v: %v %d is out of range", src.GetNamespace(), src.GetName(), name, value)
+		}
+	}
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+
+	spec := src.Spec.DeepCopy()
+	dst.Spec = NodeSimulatorSpec{
+		Replicas: int32(spec.Number),
+		Template: NodeTemplateSpec{
+			Capacity: spec.Capacity,
+			Taints:   spec.Taints,
+			Network: NodeNetworkSpec{
+				PodCIDRs:  spec.PodCIDRs,
+				Addresses: spec.Addresses,
+			},
+			Devices:    spec.Devices,
+			CSIDrivers: spec.CSIDrivers,
+			NodeInfo:   spec.NodeInfo,
+		},
+		ScaleDown:   spec.ScaleDown,
+		DriftPolicy: spec.DriftPolicy,
+	}
+	dst.Status = NodeSimulatorStatus{
+		Phase:           src.Status.Phase,
+		DesiredReplicas: int32(src.Status.DesiredNodes),
+		Replicas:        int32(src.Status.CreatedNodes),
+		Progress:        src.Status.Progress,
+	}
+	return nil
+}
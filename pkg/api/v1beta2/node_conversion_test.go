@@ -0,0 +1,118 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"math"
+	"testing"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestConversionRoundTrip(t *testing.T) {
+	maxVolumes := int64(25)
+	grace := int64(30)
+
+	tests := []struct {
+		name string
+		hub  *simv1.NodeSimulator
+	}{
+		{
+			name: "empty",
+			hub:  &simv1.NodeSimulator{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "empty"}},
+		},
+		{
+			name: "every field",
+			hub: &simv1.NodeSimulator{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        "full",
+					Labels:      map[string]string{"zone": "a"},
+					Annotations: map[string]string{"note": "b"},
+				},
+				Spec: simv1.NodeSimulatorSpec{
+					Number:    3,
+					PodCIDRs:  []string{"172.16.0.64/26"},
+					Taints:    []v1.Taint{{Key: "sim", Effect: v1.TaintEffectNoSchedule}},
+					Addresses: []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "172.17.0.5"}},
+					Capacity:  v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+					ScaleDown: &simv1.ScaleDownSpec{
+						Policy:             simv1.ScaleDownFewestPods,
+						Drain:              true,
+						GracePeriodSeconds: &grace,
+					},
+					DriftPolicy: simv1.DriftPolicyAdopt,
+					Devices:     []simv1.DeviceSpec{{ResourceName: "nvidia.com/gpu", Count: 2, Model: "V100"}},
+					CSIDrivers:  []simv1.CSIDriverSpec{{Name: "ebs.csi.aws.com", MaxVolumes: &maxVolumes}},
+					NodeInfo:    &simv1.NodeInfoSpec{KubeletVersion: "v1.20.0"},
+				},
+				Status: simv1.NodeSimulatorStatus{
+					Phase:        "Ready",
+					DesiredNodes: 3,
+					CreatedNodes: 2,
+					Progress:     "created 2/3",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spoke := &NodeSimulator{}
+			if err := spoke.ConvertFrom(test.hub); err != nil {
+				t.Fatalf("ConvertFrom: %v", err)
+			}
+			if spoke.Spec.Replicas != int32(test.hub.Spec.Number) {
+				t.Errorf("expected %d replicas, got %d", test.hub.Spec.Number, spoke.Spec.Replicas)
+			}
+			hub := &simv1.NodeSimulator{}
+			if err := spoke.ConvertTo(hub); err != nil {
+				t.Fatalf("ConvertTo: %v", err)
+			}
+			if !equality.Semantic.DeepEqual(hub, test.hub) {
+				t.Errorf("expected %+v, got %+v", test.hub, hub)
+			}
+		})
+	}
+}
+
+func TestConvertFromOutOfRange(t *testing.T) {
+	tests := []struct {
+		name string
+		hub  *simv1.NodeSimulator
+	}{
+		{
+			name: "number",
+			hub:  &simv1.NodeSimulator{Spec: simv1.NodeSimulatorSpec{Number: math.MaxInt32 + 1}},
+		},
+		{
+			name: "created nodes",
+			hub:  &simv1.NodeSimulator{Status: simv1.NodeSimulatorStatus{CreatedNodes: math.MinInt32 - 1}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := (&NodeSimulator{}).ConvertFrom(test.hub); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
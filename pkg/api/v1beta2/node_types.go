@@ -0,0 +1,117 @@
+/*
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeSimulatorSpec defines the desired state of NodeSimulator
+type NodeSimulatorSpec struct {
+	// Replicas is the number of fake nodes.
+	// +kubebuilder:validation:Minimum=0
+	Replicas int32 `json:"replicas"`
+	// Template describes every fake node.
+	// +optional
+	Template NodeTemplateSpec `json:"template,omitempty"`
+	// ScaleDown decides which nodes are removed when Replicas shrinks.
+	// +optional
+	ScaleDown *simv1.ScaleDownSpec `json:"scaleDown,omitempty"`
+	// DriftPolicy decides what happens to the fields of the fake nodes changed by
+	// others. Defaults to Heal.
+	// +optional
+	// +kubebuilder:validation:Enum=Heal;Adopt
+	DriftPolicy simv1.DriftPolicy `json:"driftPolicy,omitempty"`
+}
+
+// NodeTemplateSpec describes the fake nodes of a NodeSimulator.
+type NodeTemplateSpec struct {
+	// Capacity of the nodes, also advertised as their allocatable.
+	// +optional
+	Capacity v1.ResourceList `json:"capacity,omitempty"`
+	// Taints of the nodes.
+	// +optional
+	Taints []v1.Taint `json:"taints,omitempty"`
+	// Network of the nodes.
+	// +optional
+	Network NodeNetworkSpec `json:"network,omitempty"`
+	// Devices are advertised on every node by simulated device plugins.
+	// +optional
+	Devices []simv1.DeviceSpec `json:"devices,omitempty"`
+	// CSIDrivers are installed on every node.
+	// +optional
+	CSIDrivers []simv1.CSIDriverSpec `json:"csiDrivers,omitempty"`
+	// NodeInfo overrides the system info reported by the nodes.
+	// +optional
+	NodeInfo *simv1.NodeInfoSpec `json:"nodeInfo,omitempty"`
+}
+
+// NodeNetworkSpec describes the addresses of the fake nodes.
+type NodeNetworkSpec struct {
+	// PodCIDRs assigned to the nodes.
+	// +optional
+	PodCIDRs []string `json:"podCIDRs,omitempty"`
+	// Addresses of the nodes, a hostname address is added to every node.
+	// +optional
+	Addresses []v1.NodeAddress `json:"addresses,omitempty"`
+}
+
+// NodeSimulatorStatus defines the observed state of NodeSimulator
+type NodeSimulatorStatus struct {
+	// Phase is Creating until Replicas nodes are created, then Ready.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// DesiredReplicas is the number of fake nodes requested by the spec.
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+	// Replicas is the number of fake nodes created so far.
+	// +optional
+	Replicas int32 `json:"replicas,omitempty"`
+	// Progress summarizes the creation of the fake nodes, such as "created 3200/10000".
+	// +optional
+	Progress string `json:"progress,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.status.desiredReplicas`
+// +kubebuilder:printcolumn:name="Created",type=integer,JSONPath=`.status.replicas`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NodeSimulator is the Schema for the nodesimulators API
+type NodeSimulator struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeSimulatorSpec   `json:"spec,omitempty"`
+	Status NodeSimulatorStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodeSimulatorList contains a list of NodeSimulator
+type NodeSimulatorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeSimulator `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeSimulator{}, &NodeSimulatorList{})
+}
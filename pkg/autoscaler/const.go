@@ -0,0 +1,12 @@
+package autoscaler
+
+const (
+	// MinSizeAnnotationKey and MaxSizeAnnotationKey set the size bounds of the node
+	// group of a NodeSimulator or ClusterNodeSimulator. Only the ones with a maximum
+	// size are node groups, the minimum size defaults to 0.
+	MinSizeAnnotationKey = "sim.k8s.io/autoscaler-min-size"
+	MaxSizeAnnotationKey = "sim.k8s.io/autoscaler-max-size"
+
+	// GPULabelKey is the label the autoscaler looks for on the nodes with GPUs.
+	GPULabelKey = "sim.k8s.io/accelerator"
+)
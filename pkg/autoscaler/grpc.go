@@ -0,0 +1,63 @@
+package autoscaler
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/autoscaler/protos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"k8s.io/klog"
+)
+
+// Server serves the CloudProvider service of the cluster-autoscaler externalgrpc
+// cloud provider with Provider, over TLS or in cleartext.
+type Server struct {
+	Provider *Provider
+	// Addr is the address the gRPC server listens on.
+	Addr string
+	// CertDir holds tls.crt and tls.key, the server is cleartext when empty.
+	CertDir string
+}
+
+// Start runs the server until stop is closed, it implements manager.Runnable.
+func (s *Server) Start(stop <-chan struct{}) error {
+	opts := []grpc.ServerOption{grpc.UnaryInterceptor(logErrors)}
+	if s.CertDir != "" {
+		creds, err := credentials.NewServerTLSFromFile(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+		if err != nil {
+			return err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	server := grpc.NewServer(opts...)
+	protos.RegisterCloudProviderServer(server, s.Provider)
+
+	listener, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		klog.Infof("Starting Cluster Autoscaler gRPC Server on %v", s.Addr)
+		errChan <- server.Serve(listener)
+	}()
+
+	select {
+	case <-stop:
+		server.GracefulStop()
+		return nil
+	case err := <-errChan:
+		return err
+	}
+}
+
+// logErrors logs the errors returned to the autoscaler.
+func logErrors(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		klog.V(4).Infof("Cluster Autoscaler %v Error: %v", info.FullMethod, err)
+	}
+	return resp, err
+}
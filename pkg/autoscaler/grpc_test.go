@@ -0,0 +1,84 @@
+package autoscaler
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/autoscaler/protos"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	server := &Server{Provider: newProvider(t, newNodeSim("default", "pool", 1, sizes("1", "10"))), Addr: addr}
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- server.Start(stop) }()
+	defer func() {
+		close(stop)
+		if err := <-done; err != nil {
+			t.Errorf("Start: %v", err)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := protos.NewCloudProviderClient(conn)
+
+	tests := []struct {
+		name string
+		call func() error
+		code codes.Code
+	}{
+		{
+			name: "served by the provider",
+			call: func() error {
+				resp, err := c.NodeGroupTargetSize(ctx, &protos.NodeGroupTargetSizeRequest{Id: "default/pool"})
+				if err == nil && resp.TargetSize != 1 {
+					t.Errorf("expected target size 1, got %d", resp.TargetSize)
+				}
+				return err
+			},
+			code: codes.OK,
+		},
+		{
+			name: "error status",
+			call: func() error {
+				_, err := c.NodeGroupIncreaseSize(ctx, &protos.NodeGroupIncreaseSizeRequest{Id: "default/pool", Delta: 100})
+				return err
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			name: "unimplemented",
+			call: func() error {
+				_, err := c.PricingNodePrice(ctx, &protos.PricingNodePriceRequest{})
+				return err
+			},
+			code: codes.Unimplemented,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if code := status.Code(test.call()); code != test.code {
+				t.Errorf("expected code %v, got %v", test.code, code)
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package autoscaler
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/autoscaler/protos"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
+	"github.com/golang/protobuf/ptypes/any"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Provider exposes the NodeSimulators and ClusterNodeSimulators annotated with a
+// maximum size as the node groups of the cluster-autoscaler. A node group is
+// resized through Spec.Number, and the nodes it deletes are drained and removed by
+// the NodeSimulator controller like on scale-down. The pricing and autoscaling options
+// methods answer Unimplemented, which the autoscaler treats as not implemented by the
+// cloud provider.
+type Provider struct {
+	protos.UnimplementedCloudProviderServer
+
+	// Nodes is the NodeSimulator controller, whose client and settings are shared.
+	Nodes *node.SimReconciler
+	// APIReader reads the NodeSimulators from the API server rather than the cache,
+	// for the calls that resize the node groups.
+	APIReader client.Reader
+}
+
+// nodeGroupID returns the ID of the node group of nodeSim, the value of the owner
+// annotation of its nodes.
+func nodeGroupID(nodeSim *simv1.NodeSimulator) string {
+	return node.OwnerKey(nodeSim)
+}
+
+// nodeGroup returns the node group of nodeSim, false when it isn't one.
+func nodeGroup(nodeSim *simv1.NodeSimulator) (*protos.NodeGroup, bool) {
+	annotations := nodeSim.GetAnnotations()
+	maxValue, ok := annotations[MaxSizeAnnotationKey]
+	if !ok {
+		return nil, false
+	}
+	maxSize, err := strconv.Atoi(maxValue)
+	if err != nil || maxSize < 0 {
+		klog.Errorf("NodeSim: %v Parse Annotation %v: %q Error: %v", nodeGroupID(nodeSim), MaxSizeAnnotationKey, maxValue, err)
+		return nil, false
+	}
+	minSize := 0
+	if minValue, ok := annotations[MinSizeAnnotationKey]; ok {
+		minSize, err = strconv.Atoi(minValue)
+		if err != nil || minSize < 0 || minSize > maxSize {
+			klog.Errorf("NodeSim: %v Parse Annotation %v: %q Error: %v", nodeGroupID(nodeSim), MinSizeAnnotationKey, minValue, err)
+			return nil, false
+		}
+	}
+	return &protos.NodeGroup{
+		Id:      nodeGroupID(nodeSim),
+		MinSize: int32(minSize),
+		MaxSize: int32(maxSize),
+	}, true
+}
+
+// getNodeGroup returns the NodeSimulator view and the node group of id, read with reader.
+func (p *Provider) getNodeGroup(ctx context.Context, reader client.Reader, id string) (*simv1.NodeSimulator, *protos.NodeGroup, error) {
+	var nodeSim *simv1.NodeSimulator
+	if parts := strings.SplitN(id, "/", 2); len(parts) == 2 {
+		nodeSim = &simv1.NodeSimulator{}
+		if err := reader.Get(ctx, types.NamespacedName{Namespace: parts[0], Name: parts[1]}, nodeSim); err != nil {
+			return nil, nil, getError(id, err)
+		}
+		if !util.WatchesNamespace(p.Nodes.Namespaces, nodeSim.GetNamespace()) {
+			return nil, nil, status.Errorf(codes.NotFound, "node group %v not found", id)
+		}
+	} else {
+		clusterNodeSim := &simv1.ClusterNodeSimulator{}
+		if err := reader.Get(ctx, types.NamespacedName{Name: id}, clusterNodeSim); err != nil {
+			return nil, nil, getError(id, err)
+		}
+		nodeSim = clusterNodeSim.NodeSimulator()
+	}
+	group, ok := nodeGroup(nodeSim)
+	if !ok {
+		return nil, nil, status.Errorf(codes.NotFound, "%v is not a node group, it has no %v annotation", id, MaxSizeAnnotationKey)
+	}
+	return nodeSim, group, nil
+}
+
+func getError(id string, err error) error {
+	if apierrors.IsNotFound(err) {
+		return status.Errorf(codes.NotFound, "node group %v not found", id)
+	}
+	return status.Errorf(codes.Internal, "get node group %v: %v", id, err)
+}
+
+// updateNumber sets the number of nodes of nodeSim.
+func (p *Provider) updateNumber(ctx context.Context, nodeSim *simv1.NodeSimulator, number int) error {
+	previous := nodeSim.Spec.Number
+	nodeSim.Spec.Number = number
+	if err := p.Nodes.UpdateNodeSim(ctx, nodeSim); err != nil {
+		klog.Errorf("NodeSim: %v Update Number: %v Error: %v", nodeGroupID(nodeSim), number, err)
+		if apierrors.IsConflict(err) {
+			return status.Errorf(codes.Aborted, "update node group %v: %v", nodeGroupID(nodeSim), err)
+		}
+		return status.Errorf(codes.Internal, "update node group %v: %v", nodeGroupID(nodeSim), err)
+	}
+	klog.Infof("NodeSim: %v Resized from %v to %v nodes by the cluster-autoscaler", nodeGroupID(nodeSim), previous, number)
+	return nil
+}
+
+// NodeGroups returns all the node groups.
+func (p *Provider) NodeGroups(ctx context.Context, req *protos.NodeGroupsRequest) (*protos.NodeGroupsResponse, error) {
+	nodeSimList := &simv1.NodeSimulatorList{}
+	if err := p.Nodes.List(ctx, nodeSimList); err != nil {
+		return nil, status.Errorf(codes.Internal, "list NodeSimulators: %v", err)
+	}
+	clusterNodeSimList := &simv1.ClusterNodeSimulatorList{}
+	if err := p.Nodes.List(ctx, clusterNodeSimList); err != nil {
+		return nil, status.Errorf(codes.Internal, "list ClusterNodeSimulators: %v", err)
+	}
+
+	resp := &protos.NodeGroupsResponse{NodeGroups: make([]*protos.NodeGroup, 0)}
+	for i := range nodeSimList.Items {
+		nodeSim := &nodeSimList.Items[i]
+		if !util.WatchesNamespace(p.Nodes.Namespaces, nodeSim.GetNamespace()) {
+			continue
+		}
+		if group, ok := nodeGroup(nodeSim); ok {
+			resp.NodeGroups = append(resp.NodeGroups, group)
+		}
+	}
+	for i := range clusterNodeSimList.Items {
+		if group, ok := nodeGroup(clusterNodeSimList.Items[i].NodeSimulator()); ok {
+			resp.NodeGroups = append(resp.NodeGroups, group)
+		}
+	}
+	return resp, nil
+}
+
+// NodeGroupForNode returns the node group of a node, one with an empty ID when the
+// node belongs to none.
+func (p *Provider) NodeGroupForNode(ctx context.Context, req *protos.NodeGroupForNodeRequest) (*protos.NodeGroupForNodeResponse, error) {
+	resp := &protos.NodeGroupForNodeResponse{NodeGroup: &protos.NodeGroup{}}
+	if req.Node == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "node is required")
+	}
+	id, ok := req.Node.Annotations[node.OwnerAnnotationKey]
+	if !ok {
+		if req.Node.Labels[node.ManageLabelKey] != node.ManageLabelValue {
+			return resp, nil
+		}
+		// Sent without its annotations
+		fakeNode := &v1.Node{}
+		if err := p.Nodes.Get(ctx, types.NamespacedName{Name: req.Node.Name}, fakeNode); err != nil {
+			if apierrors.IsNotFound(err) {
+				return resp, nil
+			}
+			return nil, status.Errorf(codes.Internal, "get node %v: %v", req.Node.Name, err)
+		}
+		if id, ok = fakeNode.GetAnnotations()[node.OwnerAnnotationKey]; !ok {
+			return resp, nil
+		}
+	}
+
+	_, group, err := p.getNodeGroup(ctx, p.Nodes, id)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return resp, nil
+		}
+		return nil, err
+	}
+	resp.NodeGroup = group
+	return resp, nil
+}
+
+// GPULabel returns the label of the nodes with GPUs.
+func (p *Provider) GPULabel(ctx context.Context, req *protos.GPULabelRequest) (*protos.GPULabelResponse, error) {
+	return &protos.GPULabelResponse{Label: GPULabelKey}, nil
+}
+
+// GetAvailableGPUTypes returns no GPU types, the fake nodes have none to choose from.
+func (p *Provider) GetAvailableGPUTypes(ctx context.Context, req *protos.GetAvailableGPUTypesRequest) (*protos.GetAvailableGPUTypesResponse, error) {
+	return &protos.GetAvailableGPUTypesResponse{GpuTypes: map[string]*any.Any{}}, nil
+}
+
+// Cleanup has nothing to release.
+func (p *Provider) Cleanup(ctx context.Context, req *protos.CleanupRequest) (*protos.CleanupResponse, error) {
+	return &protos.CleanupResponse{}, nil
+}
+
+// Refresh has nothing to refresh, the node groups are read on every call.
+func (p *Provider) Refresh(ctx context.Context, req *protos.RefreshRequest) (*protos.RefreshResponse, error) {
+	return &protos.RefreshResponse{}, nil
+}
+
+// NodeGroupTargetSize returns the number of nodes of the node group.
+func (p *Provider) NodeGroupTargetSize(ctx context.Context, req *protos.NodeGroupTargetSizeRequest) (*protos.NodeGroupTargetSizeResponse, error) {
+	nodeSim, _, err := p.getNodeGroup(ctx, p.Nodes, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &protos.NodeGroupTargetSizeResponse{TargetSize: int32(nodeSim.Spec.Number)}, nil
+}
+
+// NodeGroupIncreaseSize adds delta nodes to the node group, up to its maximum size.
+func (p *Provider) NodeGroupIncreaseSize(ctx context.Context, req *protos.NodeGroupIncreaseSizeRequest) (*protos.NodeGroupIncreaseSizeResponse, error) {
+	if req.Delta <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "size increase must be positive, got %v", req.Delta)
+	}
+	nodeSim, group, err := p.getNodeGroup(ctx, p.APIReader, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	number := nodeSim.Spec.Number + int(req.Delta)
+	if number > int(group.MaxSize) {
+		return nil, status.Errorf(codes.InvalidArgument, "size increase too large, desired: %v max: %v", number, group.MaxSize)
+	}
+	if err := p.updateNumber(ctx, nodeSim, number); err != nil {
+		return nil, err
+	}
+	return &protos.NodeGroupIncreaseSizeResponse{}, nil
+}
+
+// NodeGroupDeleteNodes removes the given nodes from the node group: they are cordoned
+// and marked as draining, and the number of nodes shrinks by as many, so that the
+// NodeSimulator controller deletes these nodes and no others.
+func (p *Provider) NodeGroupDeleteNodes(ctx context.Context, req *protos.NodeGroupDeleteNodesRequest) (*protos.NodeGroupDeleteNodesResponse, error) {
+	nodeSim, group, err := p.getNodeGroup(ctx, p.APIReader, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	fakeNodes, err := p.Nodes.ListFakeNodes(ctx, nodeSim)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list nodes of node group %v: %v", req.Id, err)
+	}
+	members := make(map[string]*v1.Node, len(fakeNodes))
+	for i := range fakeNodes {
+		members[fakeNodes[i].GetName()] = &fakeNodes[i]
+	}
+
+	victims := make([]*v1.Node, 0, len(req.Nodes))
+	for _, requested := range req.Nodes {
+		name := requested.Name
+		if name == "" {
+			name = strings.TrimPrefix(requested.ProviderID, node.ProviderIDPrefix)
+		}
+		fakeNode, ok := members[name]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "node %v doesn't belong to node group %v", name, req.Id)
+		}
+		// Already removed
+		if node.IsDraining(fakeNode) {
+			continue
+		}
+		victims = append(victims, fakeNode)
+	}
+	if len(victims) == 0 {
+		return &protos.NodeGroupDeleteNodesResponse{}, nil
+	}
+	number := nodeSim.Spec.Number - len(victims)
+	if number < int(group.MinSize) {
+		return nil, status.Errorf(codes.FailedPrecondition, "size decrease too large, desired: %v min: %v", number, group.MinSize)
+	}
+
+	// The victims are marked before the number shrinks, or else the controller could
+	// pick other nodes to remove, and unmarked again when it doesn't shrink.
+	drained := make([]*v1.Node, 0, len(victims))
+	for _, victim := range victims {
+		if err := p.Nodes.StartDrain(ctx, victim.DeepCopy()); err != nil {
+			klog.Errorf("NodeSim: %v Cordon Node: %v Error: %v", req.Id, victim.GetName(), err)
+			p.stopDrain(ctx, req.Id, drained)
+			return nil, status.Errorf(codes.Internal, "cordon node %v: %v", victim.GetName(), err)
+		}
+		drained = append(drained, victim)
+	}
+	if err := p.updateNumber(ctx, nodeSim, number); err != nil {
+		p.stopDrain(ctx, req.Id, drained)
+		return nil, err
+	}
+	return &protos.NodeGroupDeleteNodesResponse{}, nil
+}
+
+// stopDrain unmarks the nodes marked by NodeGroupDeleteNodes, back to their previous
+// schedulability.
+func (p *Provider) stopDrain(ctx context.Context, id string, nodes []*v1.Node) {
+	for _, fakeNode := range nodes {
+		if err := p.Nodes.StopDrain(ctx, fakeNode.DeepCopy(), fakeNode.Spec.Unschedulable); err != nil {
+			klog.Errorf("NodeSim: %v Uncordon Node: %v Error: %v", id, fakeNode.GetName(), err)
+		}
+	}
+}
+
+// NodeGroupDecreaseTargetSize shrinks the number of nodes of the node group by the
+// nodes not created yet, it never removes existing nodes.
+func (p *Provider) NodeGroupDecreaseTargetSize(ctx context.Context, req *protos.NodeGroupDecreaseTargetSizeRequest) (*protos.NodeGroupDecreaseTargetSizeResponse, error) {
+	if req.Delta >= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "size decrease must be negative, got %v", req.Delta)
+	}
+	nodeSim, _, err := p.getNodeGroup(ctx, p.APIReader, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	fakeNodes, err := p.Nodes.ListFakeNodes(ctx, nodeSim)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list nodes of node group %v: %v", req.Id, err)
+	}
+	active := 0
+	for i := range fakeNodes {
+		if !node.IsDraining(&fakeNodes[i]) {
+			active++
+		}
+	}
+	number := nodeSim.Spec.Number + int(req.Delta)
+	if number < active {
+		return nil, status.Errorf(codes.FailedPrecondition, "attempt to delete existing nodes, target size: %v existing nodes: %v delta: %v",
+			nodeSim.Spec.Number, active, req.Delta)
+	}
+	if err := p.updateNumber(ctx, nodeSim, number); err != nil {
+		return nil, err
+	}
+	return &protos.NodeGroupDecreaseTargetSizeResponse{}, nil
+}
+
+// NodeGroupNodes returns the nodes of the node group, the draining ones as deleting.
+func (p *Provider) NodeGroupNodes(ctx context.Context, req *protos.NodeGroupNodesRequest) (*protos.NodeGroupNodesResponse, error) {
+	nodeSim, _, err := p.getNodeGroup(ctx, p.Nodes, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	fakeNodes, err := p.Nodes.ListFakeNodes(ctx, nodeSim)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list nodes of node group %v: %v", req.Id, err)
+	}
+	resp := &protos.NodeGroupNodesResponse{Instances: make([]*protos.Instance, 0, len(fakeNodes))}
+	for i := range fakeNodes {
+		state := protos.InstanceStatus_instanceRunning
+		if node.IsDraining(&fakeNodes[i]) {
+			state = protos.InstanceStatus_instanceDeleting
+		}
+		resp.Instances = append(resp.Instances, &protos.Instance{
+			Id:     node.ProviderID(fakeNodes[i].GetName()),
+			Status: &protos.InstanceStatus{InstanceState: state},
+		})
+	}
+	return resp, nil
+}
+
+// NodeGroupTemplateNodeInfo returns a ready node generated from the NodeSimulator, as
+// the nodes added to the node group would be.
+func (p *Provider) NodeGroupTemplateNodeInfo(ctx context.Context, req *protos.NodeGroupTemplateNodeInfoRequest) (*protos.NodeGroupTemplateNodeInfoResponse, error) {
+	nodeSim, _, err := p.getNodeGroup(ctx, p.Nodes, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	template, err := node.GenNode(nodeSim.DeepCopy())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "generate template node of node group %v: %v", req.Id, err)
+	}
+	name := "template-node-for-" + strings.Replace(req.Id, "/", "-", -1)
+	template.SetName(name)
+	template.Labels[v1.LabelHostname] = name
+	template.Spec.ProviderID = node.ProviderID(name)
+	template.Status.DaemonEndpoints.KubeletEndpoint.Port = p.Nodes.KubeletPort
+	template.Status.Conditions = []v1.NodeCondition{
+		{
+			Type:               v1.NodeReady,
+			Status:             v1.ConditionTrue,
+			LastHeartbeatTime:  metav1.Now(),
+			LastTransitionTime: metav1.Now(),
+		},
+	}
+	return &protos.NodeGroupTemplateNodeInfoResponse{NodeInfo: template}, nil
+}
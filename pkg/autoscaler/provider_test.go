@@ -0,0 +1,279 @@
+package autoscaler
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/autoscaler/protos"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newNodeSim(namespace, name string, number int, annotations map[string]string) *simv1.NodeSimulator {
+	return &simv1.NodeSimulator{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations},
+		Spec:       simv1.NodeSimulatorSpec{Number: number},
+	}
+}
+
+func newFakeNode(nodeSim *simv1.NodeSimulator, index int, draining bool) *v1.Node {
+	fakeNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: node.GenNodeName(nodeSim, index),
+			Labels: map[string]string{
+				node.ManageLabelKey: node.ManageLabelValue,
+				node.UniqueLabelKey: node.OwnerID(nodeSim),
+			},
+			Annotations: map[string]string{node.OwnerAnnotationKey: node.OwnerKey(nodeSim)},
+		},
+	}
+	if draining {
+		fakeNode.Annotations[node.DrainStartAnnotationKey] = "2020-01-01T00:00:00Z"
+		fakeNode.Spec.Unschedulable = true
+	}
+	return fakeNode
+}
+
+func newProvider(t *testing.T, objects ...runtime.Object) *Provider {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := simv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewFakeClientWithScheme(scheme, objects...)
+	return &Provider{
+		Nodes:     &node.SimReconciler{Client: c, Recorder: record.NewFakeRecorder(100)},
+		APIReader: c,
+	}
+}
+
+func sizes(min, max string) map[string]string {
+	return map[string]string{MinSizeAnnotationKey: min, MaxSizeAnnotationKey: max}
+}
+
+func TestNodeGroups(t *testing.T) {
+	p := newProvider(t,
+		newNodeSim("default", "pool", 1, sizes("1", "10")),
+		newNodeSim("default", "plain", 1, nil),
+		newNodeSim("default", "invalid", 1, sizes("5", "2")),
+		&simv1.ClusterNodeSimulator{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-pool", Annotations: map[string]string{MaxSizeAnnotationKey: "3"}},
+		},
+	)
+	resp, err := p.NodeGroups(context.TODO(), &protos.NodeGroupsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var groups []protos.NodeGroup
+	for _, group := range resp.NodeGroups {
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Id < groups[j].Id })
+	expected := []protos.NodeGroup{
+		{Id: "cluster-pool", MinSize: 0, MaxSize: 3},
+		{Id: "default/pool", MinSize: 1, MaxSize: 10},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Errorf("expected %v, got %v", expected, groups)
+	}
+}
+
+func TestNodeGroupIncreaseSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       string
+		delta    int32
+		code     codes.Code
+		expected int
+	}{
+		{name: "within the maximum", id: "default/pool", delta: 2, code: codes.OK, expected: 3},
+		{name: "above the maximum", id: "default/pool", delta: 10, code: codes.InvalidArgument, expected: 1},
+		{name: "not positive", id: "default/pool", delta: 0, code: codes.InvalidArgument, expected: 1},
+		{name: "not a node group", id: "default/plain", delta: 1, code: codes.NotFound},
+		{name: "missing", id: "default/missing", delta: 1, code: codes.NotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newProvider(t, newNodeSim("default", "pool", 1, sizes("1", "10")), newNodeSim("default", "plain", 1, nil))
+			_, err := p.NodeGroupIncreaseSize(context.TODO(), &protos.NodeGroupIncreaseSizeRequest{Id: test.id, Delta: test.delta})
+			if code := status.Code(err); code != test.code {
+				t.Fatalf("expected code %v, got %v", test.code, err)
+			}
+			if test.code == codes.NotFound {
+				return
+			}
+			nodeSim := &simv1.NodeSimulator{}
+			if err := p.APIReader.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "pool"}, nodeSim); err != nil {
+				t.Fatal(err)
+			}
+			if nodeSim.Spec.Number != test.expected {
+				t.Errorf("expected %d nodes, got %d", test.expected, nodeSim.Spec.Number)
+			}
+		})
+	}
+}
+
+func TestNodeGroupDeleteNodes(t *testing.T) {
+	pool := newNodeSim("default", "pool", 3, sizes("1", "10"))
+	other := newNodeSim("default", "other", 1, sizes("0", "10"))
+
+	tests := []struct {
+		name     string
+		nodes    []*protos.ExternalGrpcNode
+		code     codes.Code
+		expected int
+		drained  []string
+	}{
+		{
+			name:     "by name and providerID",
+			nodes:    []*protos.ExternalGrpcNode{{Name: "default-pool-0"}, {ProviderID: node.ProviderID("default-pool-1")}},
+			code:     codes.OK,
+			expected: 1,
+			drained:  []string{"default-pool-0", "default-pool-1", "default-pool-2"},
+		},
+		{
+			name:     "already draining node skipped",
+			nodes:    []*protos.ExternalGrpcNode{{Name: "default-pool-2"}},
+			code:     codes.OK,
+			expected: 3,
+			drained:  []string{"default-pool-2"},
+		},
+		{
+			name:     "below the minimum",
+			nodes:    []*protos.ExternalGrpcNode{{Name: "default-pool-0"}, {Name: "default-pool-1"}, {Name: "default-pool-3"}},
+			code:     codes.FailedPrecondition,
+			expected: 3,
+			drained:  []string{"default-pool-2"},
+		},
+		{
+			name:     "node of another group",
+			nodes:    []*protos.ExternalGrpcNode{{Name: "default-pool-0"}, {Name: "default-other-0"}},
+			code:     codes.InvalidArgument,
+			expected: 3,
+			drained:  []string{"default-pool-2"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newProvider(t, pool.DeepCopy(), other.DeepCopy(),
+				newFakeNode(pool, 0, false), newFakeNode(pool, 1, false), newFakeNode(pool, 2, true),
+				newFakeNode(pool, 3, false), newFakeNode(other, 0, false))
+			_, err := p.NodeGroupDeleteNodes(context.TODO(), &protos.NodeGroupDeleteNodesRequest{Id: "default/pool", Nodes: test.nodes})
+			if code := status.Code(err); code != test.code {
+				t.Fatalf("expected code %v, got %v", test.code, err)
+			}
+			nodeSim := &simv1.NodeSimulator{}
+			if err := p.APIReader.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "pool"}, nodeSim); err != nil {
+				t.Fatal(err)
+			}
+			if nodeSim.Spec.Number != test.expected {
+				t.Errorf("expected %d nodes, got %d", test.expected, nodeSim.Spec.Number)
+			}
+			var drained []string
+			nodeList := &v1.NodeList{}
+			if err := p.APIReader.List(context.TODO(), nodeList); err != nil {
+				t.Fatal(err)
+			}
+			for _, fakeNode := range nodeList.Items {
+				if node.IsDraining(&fakeNode) {
+					drained = append(drained, fakeNode.GetName())
+				}
+			}
+			sort.Strings(drained)
+			if !reflect.DeepEqual(drained, test.drained) {
+				t.Errorf("expected draining nodes %v, got %v", test.drained, drained)
+			}
+		})
+	}
+}
+
+func TestNodeGroupDecreaseTargetSize(t *testing.T) {
+	pool := newNodeSim("default", "pool", 5, sizes("0", "10"))
+
+	tests := []struct {
+		name     string
+		delta    int32
+		code     codes.Code
+		expected int
+	}{
+		{name: "nodes not created yet", delta: -3, code: codes.OK, expected: 2},
+		{name: "existing nodes", delta: -4, code: codes.FailedPrecondition, expected: 5},
+		{name: "not negative", delta: 1, code: codes.InvalidArgument, expected: 5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// Two active nodes, the draining one is on its way out
+			p := newProvider(t, pool.DeepCopy(), newFakeNode(pool, 0, false), newFakeNode(pool, 1, false), newFakeNode(pool, 2, true))
+			_, err := p.NodeGroupDecreaseTargetSize(context.TODO(), &protos.NodeGroupDecreaseTargetSizeRequest{Id: "default/pool", Delta: test.delta})
+			if code := status.Code(err); code != test.code {
+				t.Fatalf("expected code %v, got %v", test.code, err)
+			}
+			resp, err := p.NodeGroupTargetSize(context.TODO(), &protos.NodeGroupTargetSizeRequest{Id: "default/pool"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if int(resp.TargetSize) != test.expected {
+				t.Errorf("expected target size %d, got %d", test.expected, resp.TargetSize)
+			}
+		})
+	}
+}
+
+func TestNodeGroupForNode(t *testing.T) {
+	pool := newNodeSim("default", "pool", 1, sizes("0", "10"))
+	plain := newNodeSim("default", "plain", 1, nil)
+
+	tests := []struct {
+		name     string
+		node     *protos.ExternalGrpcNode
+		expected string
+	}{
+		{
+			name:     "owner annotation",
+			node:     &protos.ExternalGrpcNode{Name: "default-pool-0", Annotations: map[string]string{node.OwnerAnnotationKey: "default/pool"}},
+			expected: "default/pool",
+		},
+		{
+			name:     "fake node sent without annotations",
+			node:     &protos.ExternalGrpcNode{Name: "default-pool-0", Labels: map[string]string{node.ManageLabelKey: node.ManageLabelValue}},
+			expected: "default/pool",
+		},
+		{
+			name: "NodeSimulator without a maximum size",
+			node: &protos.ExternalGrpcNode{Name: "default-plain-0", Annotations: map[string]string{node.OwnerAnnotationKey: "default/plain"}},
+		},
+		{
+			name: "real node",
+			node: &protos.ExternalGrpcNode{Name: "worker"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := newProvider(t, pool, plain, newFakeNode(pool, 0, false))
+			resp, err := p.NodeGroupForNode(context.TODO(), &protos.NodeGroupForNodeRequest{Node: test.node})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.NodeGroup.Id != test.expected {
+				t.Errorf("expected node group %q, got %q", test.expected, resp.NodeGroup.Id)
+			}
+		})
+	}
+}
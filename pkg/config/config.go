@@ -0,0 +1,121 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	// APIVersion and Kind of the configuration file.
+	APIVersion = "config.sim.k8s.io/v1alpha1"
+	Kind       = "NodeSimulatorConfiguration"
+
+	// DefaultManagedPodSelector selects the pods run on the fake nodes.
+	DefaultManagedPodSelector = "sim.k8s.io/managed=true"
+)
+
+// NodeSimulatorConfiguration is the component config of the simulator, loaded from
+// the file of the --config flag.
+type NodeSimulatorConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// ClientConnection sets the rate limits of the API server client.
+	ClientConnection ClientConnection `json:"clientConnection"`
+	// NodeSyncWorkers is the number of fake nodes of a NodeSimulator synced in parallel.
+	NodeSyncWorkers int `json:"nodeSyncWorkers"`
+	// HeartbeatWorkers is the number of fake nodes heartbeated in parallel.
+	HeartbeatWorkers int `json:"heartbeatWorkers"`
+	// PodWorkers is the number of pods whose status is synced in parallel.
+	PodWorkers int `json:"podWorkers"`
+	// Namespaces whose NodeSimulators and pods are handled, all of them when empty.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// ManagedPodSelector is the label selector of the pods run on the fake nodes.
+	ManagedPodSelector string `json:"managedPodSelector"`
+}
+
+// ClientConnection sets the rate limits of the API server client.
+type ClientConnection struct {
+	QPS   float32 `json:"qps"`
+	Burst int     `json:"burst"`
+}
+
+// Default returns the configuration used when no file is given.
+func Default() *NodeSimulatorConfiguration {
+	return &NodeSimulatorConfiguration{
+		TypeMeta: metav1.TypeMeta{APIVersion: APIVersion, Kind: Kind},
+		ClientConnection: ClientConnection{
+			QPS:   1000,
+			Burst: 1000,
+		},
+		NodeSyncWorkers:    util.Workers,
+		HeartbeatWorkers:   5,
+		PodWorkers:         1,
+		ManagedPodSelector: DefaultManagedPodSelector,
+	}
+}
+
+// Load reads a configuration file, YAML or JSON, on top of the defaults. Unknown
+// fields are rejected.
+func Load(path string) (*NodeSimulatorConfiguration, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = yaml.ToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("config %v: %v", path, err)
+	}
+
+	config := Default()
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("config %v: %v", path, err)
+	}
+	if config.APIVersion != APIVersion || config.Kind != Kind {
+		return nil, fmt.Errorf("config %v: unsupported %v %v, expected %v %v", path, config.APIVersion, config.Kind, APIVersion, Kind)
+	}
+	return config, nil
+}
+
+// Validate checks the values of the configuration.
+func (c *NodeSimulatorConfiguration) Validate() error {
+	errs := field.ErrorList{}
+	if c.ClientConnection.QPS <= 0 {
+		errs = append(errs, field.Invalid(field.NewPath("clientConnection", "qps"), c.ClientConnection.QPS, "must be greater than 0"))
+	}
+	if c.ClientConnection.Burst <= 0 {
+		errs = append(errs, field.Invalid(field.NewPath("clientConnection", "burst"), c.ClientConnection.Burst, "must be greater than 0"))
+	}
+	workers := []struct {
+		name  string
+		value int
+	}{
+		{"nodeSyncWorkers", c.NodeSyncWorkers},
+		{"heartbeatWorkers", c.HeartbeatWorkers},
+		{"podWorkers", c.PodWorkers},
+	}
+	for _, worker := range workers {
+		if worker.value <= 0 {
+			errs = append(errs, field.Invalid(field.NewPath(worker.name), worker.value, "must be greater than 0"))
+		}
+	}
+	for i, namespace := range c.Namespaces {
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			errs = append(errs, field.Invalid(field.NewPath("namespaces").Index(i), namespace, msg))
+		}
+	}
+	if _, err := labels.Parse(c.ManagedPodSelector); err != nil {
+		errs = append(errs, field.Invalid(field.NewPath("managedPodSelector"), c.ManagedPodSelector, err.Error()))
+	}
+	return errs.ToAggregate()
+}
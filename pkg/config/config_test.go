@@ -0,0 +1,145 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name     string
+		data     string
+		expected func(config *NodeSimulatorConfiguration)
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			data:     "apiVersion: config.sim.k8s.io/v1alpha1\nkind: NodeSimulatorConfiguration\n",
+			expected: func(config *NodeSimulatorConfiguration) {},
+		},
+		{
+			name: "yaml over the defaults",
+			data: `apiVersion: config.sim.k8s.io/v1alpha1
+kind: NodeSimulatorConfiguration
+clientConnection:
+  qps: 50
+  burst: 100
+podWorkers: 4
+namespaces: [team-a, team-b]
+`,
+			expected: func(config *NodeSimulatorConfiguration) {
+				config.ClientConnection = ClientConnection{QPS: 50, Burst: 100}
+				config.PodWorkers = 4
+				config.Namespaces = []string{"team-a", "team-b"}
+			},
+		},
+		{
+			name: "json",
+			data: `{"apiVersion": "config.sim.k8s.io/v1alpha1", "kind": "NodeSimulatorConfiguration", "heartbeatWorkers": 8}`,
+			expected: func(config *NodeSimulatorConfiguration) {
+				config.HeartbeatWorkers = 8
+			},
+		},
+		{
+			name:    "unknown field",
+			data:    "apiVersion: config.sim.k8s.io/v1alpha1\nkind: NodeSimulatorConfiguration\npodWorker: 4\n",
+			wantErr: true,
+		},
+		{
+			name:    "wrong kind",
+			data:    "apiVersion: config.sim.k8s.io/v1alpha1\nkind: KubeletConfiguration\n",
+			wantErr: true,
+		},
+		{
+			name:    "wrong apiVersion",
+			data:    "apiVersion: config.sim.k8s.io/v1\nkind: NodeSimulatorConfiguration\n",
+			wantErr: true,
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".yaml")
+			if err := ioutil.WriteFile(path, []byte(test.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			config, err := Load(path)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("expected error %v, got %v", test.wantErr, err)
+			}
+			if test.wantErr {
+				return
+			}
+			expected := Default()
+			test.expected(expected)
+			if !reflect.DeepEqual(config, expected) {
+				t.Errorf("expected %+v, got %+v", expected, config)
+			}
+		})
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(config *NodeSimulatorConfiguration)
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			mutate: func(config *NodeSimulatorConfiguration) {},
+		},
+		{
+			name:    "zero qps",
+			mutate:  func(config *NodeSimulatorConfiguration) { config.ClientConnection.QPS = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "negative burst",
+			mutate:  func(config *NodeSimulatorConfiguration) { config.ClientConnection.Burst = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "zero workers",
+			mutate:  func(config *NodeSimulatorConfiguration) { config.HeartbeatWorkers = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "invalid namespace",
+			mutate:  func(config *NodeSimulatorConfiguration) { config.Namespaces = []string{"Team_A"} },
+			wantErr: true,
+		},
+		{
+			name:   "set based selector",
+			mutate: func(config *NodeSimulatorConfiguration) { config.ManagedPodSelector = "app in (a, b),!skip" },
+		},
+		{
+			name:    "invalid selector",
+			mutate:  func(config *NodeSimulatorConfiguration) { config.ManagedPodSelector = "app in (" },
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := Default()
+			test.mutate(config)
+			if err := config.Validate(); (err != nil) != test.wantErr {
+				t.Errorf("expected error %v, got %v", test.wantErr, err)
+			}
+		})
+	}
+}
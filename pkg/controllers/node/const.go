@@ -1,6 +1,13 @@
 package node
 
-import v1 "k8s.io/api/core/v1"
+import (
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
 
 const (
 	NodeSimFinalizer   = "sim.k8s.io/NodeFinal"
@@ -8,6 +15,7 @@ const (
 	ManageLabelKey     = "sim.k8s.io/managed"
 	ManageLabelValue   = "true"
 	UniqueLabelKey     = "sim.k8s.io/id"
+	NodeLeaseNamespace = "kube-node-lease"
 	NodeOS             = "linux"
 	NodeArch           = "amd64"
 	NodeOSImage        = "CentOS Linux 7 (Core)"
@@ -15,20 +23,131 @@ const (
 	NodeKubeletVersion = "v1.19.1"
 	NodeDockerVersion  = "docker://18.6.3"
 
+	// FieldManager is the field manager recorded for the writes of the simulator.
+	FieldManager = "node-simulator"
+	// ProviderIDPrefix prefixes the name of a fake node in its providerID.
+	ProviderIDPrefix = "nodesimulator://"
+
+	// Annotation
+	DrainStartAnnotationKey  = "sim.k8s.io/drain-start"
+	OwnedTaintsAnnotationKey = "sim.k8s.io/owned-taints"
+	// TemplateHashAnnotationKey holds the hash of the template a node was last synced from.
+	TemplateHashAnnotationKey = "sim.k8s.io/template-hash"
+	// OwnerAnnotationKey holds the namespace/name of the NodeSimulator of a node and
+	// OwnerUIDAnnotationKey its UID.
+	OwnerAnnotationKey    = "sim.k8s.io/owner"
+	OwnerUIDAnnotationKey = "sim.k8s.io/owner-uid"
+	// DevicesAnnotationKey holds the DeviceSpecs of a node, UnhealthyDevicesAnnotationKey
+	// lists the comma separated IDs of its unhealthy devices and
+	// AllocatedDevicesAnnotationKey the PodDevices allocated to a pod.
+	DevicesAnnotationKey          = "sim.k8s.io/devices"
+	UnhealthyDevicesAnnotationKey = "sim.k8s.io/unhealthy-devices"
+	AllocatedDevicesAnnotationKey = "sim.k8s.io/allocated-devices"
+	// CSIDriversAnnotationKey holds the CSIDriverSpecs of a node.
+	CSIDriversAnnotationKey = "sim.k8s.io/csi-drivers"
+	// NodeNamePlaceholder is replaced by the node name in the node ID of a CSI driver.
+	NodeNamePlaceholder = "$(NODE_NAME)"
+	// ControllerManagedAttachAnnotationKey lets the attach-detach controller attach
+	// the volumes of the node, like a kubelet with enable-controller-attach-detach.
+	ControllerManagedAttachAnnotationKey = "volumes.kubernetes.io/controller-managed-attach-detach"
+
+	// AttachLimitResourcePrefix prefixes the allocatable attach limit of a CSI driver.
+	AttachLimitResourcePrefix = "attachable-volumes-csi-"
+	// CSIVolumeNamePrefix prefixes the unique name of a CSI volume.
+	CSIVolumeNamePrefix = "kubernetes.io/csi/"
+
+	// SCV labels of the pods, the memory in MiB used on each of number cards.
+	SCVMemoryLabelKey = "scv/memory"
+	SCVNumberLabelKey = "scv/number"
+	// SCVUpdateInterval is the update interval in milliseconds recorded in the Scv objects.
+	SCVUpdateInterval = 20000
+
 	// Condition
-	KubeletMessage      = "kubelet is ready."
-	DiskMessage         = "kubelet has sufficient disk space available"
-	MemoryMessage       = "kubelet has sufficient memory available"
-	DiskPressureMessage = "kubelet has no disk pressure"
-	RouteMessage        = "RouteController created a route"
+	KubeletMessage            = "kubelet is ready."
+	DiskMessage               = "kubelet has sufficient disk space available"
+	MemoryMessage             = "kubelet has sufficient memory available"
+	DiskPressureMessage       = "kubelet has no disk pressure"
+	InsufficientMemoryMessage = "kubelet has insufficient memory available"
+	RouteMessage              = "RouteController created a route"
 
 	// Reason
-	KubeletReason      = "KubeletReady"
-	DiskReason         = "KubeletHasSufficientDisk"
-	MemoryReason       = "MemoryPressure"
-	DiskPressureReason = "KubeletHasNoDiskPressure"
-	RouteReason        = "RouteCreated"
+	KubeletReason            = "KubeletReady"
+	DiskReason               = "KubeletHasSufficientDisk"
+	MemoryReason             = "MemoryPressure"
+	DiskPressureReason       = "KubeletHasNoDiskPressure"
+	InsufficientMemoryReason = "KubeletHasInsufficientMemory"
+	RouteReason              = "RouteCreated"
 
 	// Type
 	OutOfDiskPressure v1.NodeConditionType = "OutOfDisk"
+
+	// Event Reason
+	StartingEventReason     = "Starting"
+	DrainingEventReason     = "NodeDraining"
+	EvictedEventReason      = "Evicted"
+	FailedCreateEventReason = "FailedCreate"
+	FailedSyncEventReason   = "FailedSync"
+	// OwnershipConflictEventReason is recorded when a node of another owner is in the way.
+	OwnershipConflictEventReason = "OwnershipConflict"
+
+	// MemoryEvictionThreshold is the available memory below which a node reports MemoryPressure.
+	MemoryEvictionThreshold = "100Mi"
+
+	// CreateBatchSize is the number of fake nodes created between two progress reports.
+	CreateBatchSize = 500
+
+	// Phase of a NodeSimulator
+	NodeSimCreatingPhase = "Creating"
+	NodeSimReadyPhase    = "Ready"
+
+	// DrainRequeuePeriod is how often a draining node is checked for remaining pods.
+	DrainRequeuePeriod = 5 * time.Second
+	// CreateRequeuePeriod is how often the missing nodes of a NodeSimulator are
+	// created again, and counted again while other replicas create theirs.
+	CreateRequeuePeriod = 10 * time.Second
 )
+
+// ConditionEventReasons holds the event reasons of a node condition transition.
+type ConditionEventReasons struct {
+	HealthyStatus v1.ConditionStatus
+	Healthy       string
+	Unhealthy     string
+}
+
+// NodeConditionEventReasons maps node conditions to the events the kubelet records
+// when they change.
+var NodeConditionEventReasons = map[v1.NodeConditionType]ConditionEventReasons{
+	v1.NodeReady:          {HealthyStatus: v1.ConditionTrue, Healthy: "NodeReady", Unhealthy: "NodeNotReady"},
+	v1.NodeMemoryPressure: {HealthyStatus: v1.ConditionFalse, Healthy: "NodeHasSufficientMemory", Unhealthy: "NodeHasInsufficientMemory"},
+	v1.NodeDiskPressure:   {HealthyStatus: v1.ConditionFalse, Healthy: "NodeHasNoDiskPressure", Unhealthy: "NodeHasDiskPressure"},
+	v1.NodePIDPressure:    {HealthyStatus: v1.ConditionFalse, Healthy: "NodeHasSufficientPID", Unhealthy: "NodeHasInsufficientPID"},
+	OutOfDiskPressure:     {HealthyStatus: v1.ConditionFalse, Healthy: "NodeHasSufficientDisk", Unhealthy: "NodeOutOfDisk"},
+}
+
+// ManagedPodSelector selects the pods run on the fake nodes. It is set from the
+// configuration at startup.
+var ManagedPodSelector = labels.SelectorFromSet(labels.Set{ManageLabelKey: ManageLabelValue})
+
+// IsManagedPod reports whether the pod is run on the fake nodes.
+func IsManagedPod(pod *v1.Pod) bool {
+	return ManagedPodSelector.Matches(labels.Set(pod.GetLabels()))
+}
+
+// ManagedPodLabels returns labels matched by ManagedPodSelector, for the pods created
+// to run on the fake nodes. It fails when the selector matches no labels it can derive.
+func ManagedPodLabels() (labels.Set, error) {
+	set := labels.Set{}
+	requirements, _ := ManagedPodSelector.Requirements()
+	for _, requirement := range requirements {
+		switch requirement.Operator() {
+		case selection.Equals, selection.DoubleEquals, selection.In:
+			set[requirement.Key()] = requirement.Values().List()[0]
+		case selection.Exists:
+			set[requirement.Key()] = ManageLabelValue
+		}
+	}
+	if !ManagedPodSelector.Matches(set) {
+		return nil, fmt.Errorf("no labels match the managed pod selector %q", ManagedPodSelector.String())
+	}
+	return set, nil
+}
@@ -0,0 +1,102 @@
+package node
+
+import (
+	"context"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/shard"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// AttachReconciler emulates the external-attacher of the CSI drivers installed on
+// the fake nodes: their VolumeAttachments are reported attached as soon as the
+// attach-detach controller creates them, and simply go away when it deletes them.
+type AttachReconciler struct {
+	Client client.Client
+	// Shard selects the nodes whose volumes are attached by this replica, all of them when nil.
+	Shard *shard.Sharder
+}
+
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=volumeattachments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=volumeattachments/status,verbs=get;update;patch
+
+func (r *AttachReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	builder := ctrl.NewControllerManagedBy(mgr).
+		Named("volumeattacher").
+		For(&storagev1.VolumeAttachment{})
+	if r.Shard != nil {
+		// Attach the pending volumes of the nodes this replica took over.
+		rebalance := make(chan event.GenericEvent)
+		r.Shard.OnChange(func() {
+			attachments := &storagev1.VolumeAttachmentList{}
+			if err := r.Client.List(context.TODO(), attachments); err != nil {
+				klog.Errorf("Rebalance List VolumeAttachment Error: %v", err)
+				return
+			}
+			for i := range attachments.Items {
+				attachment := &attachments.Items[i]
+				if !attachment.Status.Attached && r.Shard.Owns(attachment.Spec.NodeName) {
+					rebalance <- event.GenericEvent{Meta: attachment, Object: attachment}
+				}
+			}
+		})
+		builder = builder.Watches(&source.Channel{Source: rebalance}, &handler.EnqueueRequestForObject{})
+	}
+	return builder.Complete(r)
+}
+
+func (r *AttachReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	var (
+		ctx        = context.Background()
+		attachment = &storagev1.VolumeAttachment{}
+		err        = r.Client.Get(ctx, req.NamespacedName, attachment)
+	)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Errorf("VolumeAttachment: %v Error: %v ", req.Name, err)
+		}
+		return ctrl.Result{}, nil
+	}
+	if attachment.GetDeletionTimestamp() != nil || attachment.Status.Attached {
+		return ctrl.Result{}, nil
+	}
+
+	fakeNode := &v1.Node{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: attachment.Spec.NodeName}, fakeNode); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if fakeNode.GetLabels()[ManageLabelKey] != ManageLabelValue || !r.Shard.Owns(fakeNode.GetName()) {
+		return ctrl.Result{}, nil
+	}
+	drivers, err := NodeCSIDrivers(fakeNode)
+	if err != nil {
+		klog.Errorf("VolumeAttachment: %v Get CSI Drivers of Node: %v Error: %v", req.Name, fakeNode.GetName(), err)
+		return ctrl.Result{}, nil
+	}
+	installed := false
+	for _, driver := range drivers {
+		installed = installed || driver.Name == attachment.Spec.Attacher
+	}
+	if !installed {
+		return ctrl.Result{}, nil
+	}
+
+	attachment.Status.Attached = true
+	attachment.Status.AttachError = nil
+	if err := r.Client.Status().Update(ctx, attachment); err != nil {
+		klog.Errorf("VolumeAttachment: %v Attach Error: %v", req.Name, err)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,67 @@
+package node
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAttachReconcile(t *testing.T) {
+	drivers, _ := json.Marshal([]simv1.CSIDriverSpec{{Name: "ebs.csi.aws.com"}})
+	fakeNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "fake-0",
+		Labels:      map[string]string{ManageLabelKey: ManageLabelValue},
+		Annotations: map[string]string{CSIDriversAnnotationKey: string(drivers)},
+	}}
+	realNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:        "real",
+		Annotations: map[string]string{CSIDriversAnnotationKey: string(drivers)},
+	}}
+	newAttachment := func(attacher, nodeName string) *storagev1.VolumeAttachment {
+		pvName := "pv"
+		return &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: "attachment"},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: attacher,
+				NodeName: nodeName,
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		attachment *storagev1.VolumeAttachment
+		expected   bool
+	}{
+		{name: "installed driver", attachment: newAttachment("ebs.csi.aws.com", "fake-0"), expected: true},
+		{name: "driver not installed", attachment: newAttachment("pd.csi.storage.gke.io", "fake-0")},
+		{name: "real node", attachment: newAttachment("ebs.csi.aws.com", "real")},
+		{name: "missing node", attachment: newAttachment("ebs.csi.aws.com", "fake-9")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &AttachReconciler{Client: fake.NewFakeClientWithScheme(clientgoscheme.Scheme, fakeNode.DeepCopy(), realNode.DeepCopy(), test.attachment)}
+			if _, err := r.Reconcile(ctrl.Request{NamespacedName: types.NamespacedName{Name: "attachment"}}); err != nil {
+				t.Fatal(err)
+			}
+			attachment := &storagev1.VolumeAttachment{}
+			if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "attachment"}, attachment); err != nil {
+				t.Fatal(err)
+			}
+			if attachment.Status.Attached != test.expected {
+				t.Errorf("expected attached %v, got %v", test.expected, attachment.Status.Attached)
+			}
+		})
+	}
+}
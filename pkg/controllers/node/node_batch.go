@@ -0,0 +1,274 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CreateBackoff is the back-off of the node creations throttled or failed by the API server.
+var CreateBackoff = wait.Backoff{
+	Duration: 200 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+	Steps:    6,
+}
+
+// NodeChanged reports whether the live node differs from the template in the fields
+// owned by the simulator.
+func NodeChanged(live, template *v1.Node) bool {
+	spec := MergeNodeSpec(live, template)
+	if !equality.Semantic.DeepEqual(spec.Taints, live.Spec.Taints) || spec.PodCIDR != live.Spec.PodCIDR {
+		return true
+	}
+	// The providerID can only be set once, on the nodes created without one
+	if live.Spec.ProviderID == "" && template.Spec.ProviderID != "" {
+		return true
+	}
+	for key, value := range template.GetLabels() {
+		if current, ok := live.GetLabels()[key]; !ok || current != value {
+			return true
+		}
+	}
+	for key, value := range template.GetAnnotations() {
+		if current, ok := live.GetAnnotations()[key]; !ok || current != value {
+			return true
+		}
+	}
+	for _, key := range []string{DevicesAnnotationKey, CSIDriversAnnotationKey} {
+		_, desired := template.GetAnnotations()[key]
+		_, current := live.GetAnnotations()[key]
+		if desired != current {
+			return true
+		}
+	}
+	return !equality.Semantic.DeepEqual(live.Status.Capacity, template.Status.Capacity) ||
+		!equality.Semantic.DeepEqual(live.Status.Addresses, template.Status.Addresses) ||
+		live.Status.DaemonEndpoints != template.Status.DaemonEndpoints ||
+		!nodeInfoEqual(live.Status.NodeInfo, template.Status.NodeInfo)
+}
+
+// nodeInfoEqual compares the fields of the system info set from the spec.
+func nodeInfoEqual(live, template v1.NodeSystemInfo) bool {
+	return live.OperatingSystem == template.OperatingSystem && live.Architecture == template.Architecture &&
+		live.OSImage == template.OSImage && live.KernelVersion == template.KernelVersion &&
+		live.KubeletVersion == template.KubeletVersion && live.KubeProxyVersion == template.KubeProxyVersion &&
+		live.ContainerRuntimeVersion == template.ContainerRuntimeVersion
+}
+
+// PatchFakeNode patches the live node with the fields of the template it differs in.
+func (r *SimReconciler) PatchFakeNode(ctx context.Context, nodeSim *simv1.NodeSimulator, fakeNode, node *v1.Node) {
+	spec := MergeNodeSpec(fakeNode, node)
+	specOps := []util.Ops{
+		{
+			Op:    "add",
+			Path:  "/spec/taints",
+			Value: spec.Taints,
+		},
+	}
+	if spec.PodCIDR != fakeNode.Spec.PodCIDR {
+		specOps = append(specOps, util.Ops{
+			Op:    "add",
+			Path:  "/spec/podCIDR",
+			Value: spec.PodCIDR,
+		}, util.Ops{
+			Op:    "add",
+			Path:  "/spec/podCIDRs",
+			Value: spec.PodCIDRs,
+		})
+	}
+	if fakeNode.Spec.ProviderID == "" && node.Spec.ProviderID != "" {
+		specOps = append(specOps, util.Ops{
+			Op:    "add",
+			Path:  "/spec/providerID",
+			Value: node.Spec.ProviderID,
+		})
+	}
+	if fakeNode.GetLabels() == nil {
+		specOps = append(specOps, util.Ops{
+			Op:    "add",
+			Path:  "/metadata/labels",
+			Value: node.GetLabels(),
+		})
+	} else {
+		for key, value := range node.GetLabels() {
+			if current, ok := fakeNode.GetLabels()[key]; ok && current == value {
+				continue
+			}
+			specOps = append(specOps, util.Ops{
+				Op:    "add",
+				Path:  "/metadata/labels/" + util.EscapeJSONPointer(key),
+				Value: value,
+			})
+		}
+	}
+	if fakeNode.GetAnnotations() == nil {
+		specOps = append(specOps, util.Ops{
+			Op:    "add",
+			Path:  "/metadata/annotations",
+			Value: node.GetAnnotations(),
+		})
+	} else {
+		for key, value := range node.GetAnnotations() {
+			if current, ok := fakeNode.GetAnnotations()[key]; ok && current == value {
+				continue
+			}
+			specOps = append(specOps, util.Ops{
+				Op:    "add",
+				Path:  "/metadata/annotations/" + util.EscapeJSONPointer(key),
+				Value: value,
+			})
+		}
+		for _, key := range []string{DevicesAnnotationKey, CSIDriversAnnotationKey} {
+			if _, ok := node.GetAnnotations()[key]; !ok {
+				if _, ok := fakeNode.GetAnnotations()[key]; ok {
+					specOps = append(specOps, util.Ops{
+						Op:   "remove",
+						Path: "/metadata/annotations/" + util.EscapeJSONPointer(key),
+					})
+				}
+			}
+		}
+	}
+
+	if err := r.Client.Patch(ctx, node, &util.Patch{PatchOps: specOps}, client.FieldOwner(FieldManager)); err != nil {
+		klog.Errorf("NodeSim: %v/%v Patch Node: %v Error: %v ", nodeSim.GetNamespace(), nodeSim.GetName(), node.GetName(), err)
+	}
+
+	if !equality.Semantic.DeepEqual(fakeNode.Status.Capacity, node.Status.Capacity) ||
+		!equality.Semantic.DeepEqual(fakeNode.Status.Addresses, node.Status.Addresses) ||
+		fakeNode.Status.DaemonEndpoints != node.Status.DaemonEndpoints ||
+		!nodeInfoEqual(fakeNode.Status.NodeInfo, node.Status.NodeInfo) {
+		newNode := fakeNode.DeepCopy()
+		newNode.Status.Allocatable = node.Status.Allocatable
+		newNode.Status.Capacity = node.Status.Capacity
+		newNode.Status.Addresses = node.Status.Addresses
+		newNode.Status.DaemonEndpoints = node.Status.DaemonEndpoints
+		newNode.Status.NodeInfo.OperatingSystem = node.Status.NodeInfo.OperatingSystem
+		newNode.Status.NodeInfo.Architecture = node.Status.NodeInfo.Architecture
+		newNode.Status.NodeInfo.OSImage = node.Status.NodeInfo.OSImage
+		newNode.Status.NodeInfo.KernelVersion = node.Status.NodeInfo.KernelVersion
+		newNode.Status.NodeInfo.KubeletVersion = node.Status.NodeInfo.KubeletVersion
+		newNode.Status.NodeInfo.KubeProxyVersion = node.Status.NodeInfo.KubeProxyVersion
+		newNode.Status.NodeInfo.ContainerRuntimeVersion = node.Status.NodeInfo.ContainerRuntimeVersion
+		_, _, err := util.PatchNodeStatus(r.ClientSet.CoreV1(), types.NodeName(node.GetName()), fakeNode, newNode)
+		if err != nil {
+			klog.Errorf("Patch Node: %v Error: %v", newNode.GetName(), err)
+		}
+	}
+
+	// The CSINodes of the active nodes are synced by SyncFakeNode while the
+	// NodeSimulator has CSI drivers
+	if len(nodeSim.Spec.CSIDrivers) == 0 && fakeNode.GetAnnotations()[CSIDriversAnnotationKey] != "" {
+		if err := r.DeleteCSINode(ctx, fakeNode.GetName()); err != nil {
+			klog.Errorf("NodeSim: %v/%v Delete CSINode: %v Error: %v ", nodeSim.GetNamespace(), nodeSim.GetName(), node.GetName(), err)
+		}
+	}
+}
+
+// CreateFakeNode creates the node, backing off while the API server throttles the
+// requests or fails with a server error. It returns whether the node exists.
+func (r *SimReconciler) CreateFakeNode(ctx context.Context, nodeSim *simv1.NodeSimulator, node *v1.Node) bool {
+	var (
+		createErr error
+		created   *v1.Node
+	)
+	err := wait.ExponentialBackoff(CreateBackoff, func() (bool, error) {
+		created = node.DeepCopy()
+		createErr = r.Client.Create(ctx, created, client.FieldOwner(FieldManager))
+		switch {
+		case createErr == nil:
+			return true, nil
+		case apierrors.IsAlreadyExists(createErr):
+			existing := &v1.Node{}
+			if err := r.Client.Get(ctx, types.NamespacedName{Name: node.GetName()}, existing); err != nil {
+				return false, err
+			}
+			if IsForeign(existing, nodeSim) {
+				return false, fmt.Errorf("node %v is owned by %v", node.GetName(), existing.GetAnnotations()[OwnerAnnotationKey])
+			}
+			created = existing
+			return true, nil
+		case isRetriable(createErr):
+			if delay, ok := apierrors.SuggestsClientDelay(createErr); ok {
+				time.Sleep(time.Duration(delay) * time.Second)
+			}
+			return false, nil
+		default:
+			return false, createErr
+		}
+	})
+	if err == wait.ErrWaitTimeout {
+		err = createErr
+	}
+	if err != nil {
+		klog.Errorf("NodeSim: %v/%v Create Node: %v Error: %v ", nodeSim.GetNamespace(), nodeSim.GetName(), node.GetName(), err)
+		r.Recorder.Eventf(nodeSim, v1.EventTypeWarning, FailedCreateEventReason, "Create Node %v Error: %v", node.GetName(), err)
+		return false
+	}
+	if createErr == nil {
+		r.Recorder.Event(created, v1.EventTypeNormal, StartingEventReason, "Starting kubelet.")
+	}
+	// Also when created by an earlier attempt, whose CSINode may be missing
+	if len(nodeSim.Spec.CSIDrivers) > 0 {
+		if err := r.SyncCSINode(ctx, created, nodeSim.Spec.CSIDrivers); err != nil {
+			klog.Errorf("NodeSim: %v/%v Sync CSINode: %v Error: %v ", nodeSim.GetNamespace(), nodeSim.GetName(), node.GetName(), err)
+		}
+	}
+	return true
+}
+
+func isRetriable(err error) bool {
+	return apierrors.IsTooManyRequests(err) || apierrors.IsInternalError(err) || apierrors.IsServerTimeout(err) ||
+		apierrors.IsServiceUnavailable(err) || apierrors.IsTimeout(err) || apierrors.IsUnexpectedServerError(err)
+}
+
+// ReportsStatus reports whether the replica writes the status of nodeSim. With
+// sharding a single replica does, counting the nodes created by the others on the
+// next sync.
+func (r *SimReconciler) ReportsStatus(nodeSim *simv1.NodeSimulator) bool {
+	return r.Shard.Owns(OwnerKey(nodeSim))
+}
+
+// SyncStatus reports the number of created nodes in the status of nodeSim, on the
+// replica that ReportsStatus.
+func (r *SimReconciler) SyncStatus(ctx context.Context, nodeSim *simv1.NodeSimulator, created int) {
+	if !r.ReportsStatus(nodeSim) {
+		return
+	}
+	status := simv1.NodeSimulatorStatus{
+		Phase:        NodeSimReadyPhase,
+		DesiredNodes: nodeSim.Spec.Number,
+		CreatedNodes: created,
+		Progress:     fmt.Sprintf("created %d/%d", created, nodeSim.Spec.Number),
+	}
+	if created < nodeSim.Spec.Number {
+		status.Phase = NodeSimCreatingPhase
+	}
+	if equality.Semantic.DeepEqual(status, nodeSim.Status) {
+		return
+	}
+
+	ops := []util.Ops{
+		{
+			Op:    "add",
+			Path:  "/status",
+			Value: status,
+		},
+	}
+	if err := r.Client.Status().Patch(ctx, Persisted(nodeSim), &util.Patch{PatchOps: ops}); err != nil {
+		klog.Errorf("NodeSim: %v/%v Patch Status Error: %v", nodeSim.GetNamespace(), nodeSim.GetName(), err)
+		return
+	}
+	nodeSim.Status = status
+}
@@ -0,0 +1,109 @@
+package node
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeChanged(t *testing.T) {
+	template := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{ManageLabelKey: ManageLabelValue, "zone": "a"},
+			Annotations: map[string]string{OwnerAnnotationKey: "default/sim"},
+		},
+		Spec: v1.NodeSpec{
+			PodCIDR:    "10.0.0.0/24",
+			PodCIDRs:   []string{"10.0.0.0/24"},
+			ProviderID: ProviderID("default-sim-0"),
+			Taints:     []v1.Taint{{Key: "sim", Effect: v1.TaintEffectNoSchedule}},
+		},
+		Status: v1.NodeStatus{
+			Capacity: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+			NodeInfo: v1.NodeSystemInfo{KubeletVersion: NodeKubeletVersion},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(live *v1.Node)
+		expected bool
+	}{
+		{
+			name:   "unchanged",
+			mutate: func(live *v1.Node) {},
+		},
+		{
+			name: "labels and annotations added by others",
+			mutate: func(live *v1.Node) {
+				live.Labels["team"] = "x"
+				live.Annotations["note"] = "y"
+			},
+		},
+		{
+			name:     "template label changed",
+			mutate:   func(live *v1.Node) { live.Labels["zone"] = "b" },
+			expected: true,
+		},
+		{
+			name:     "template label removed",
+			mutate:   func(live *v1.Node) { delete(live.Labels, "zone") },
+			expected: true,
+		},
+		{
+			name:     "template annotation changed",
+			mutate:   func(live *v1.Node) { live.Annotations[OwnerAnnotationKey] = "default/other" },
+			expected: true,
+		},
+		{
+			name:     "devices annotation left over",
+			mutate:   func(live *v1.Node) { live.Annotations[DevicesAnnotationKey] = "[]" },
+			expected: true,
+		},
+		{
+			name:     "template taint removed",
+			mutate:   func(live *v1.Node) { live.Spec.Taints = nil },
+			expected: true,
+		},
+		{
+			name: "taint added by others",
+			mutate: func(live *v1.Node) {
+				live.Spec.Taints = append([]v1.Taint{{Key: "user", Effect: v1.TaintEffectNoExecute}}, live.Spec.Taints...)
+			},
+		},
+		{
+			name:     "providerID missing",
+			mutate:   func(live *v1.Node) { live.Spec.ProviderID = "" },
+			expected: true,
+		},
+		{
+			name:     "capacity changed",
+			mutate:   func(live *v1.Node) { live.Status.Capacity[v1.ResourceCPU] = resource.MustParse("8") },
+			expected: true,
+		},
+		{
+			name:     "system info changed",
+			mutate:   func(live *v1.Node) { live.Status.NodeInfo.KubeletVersion = "v1.0.0" },
+			expected: true,
+		},
+		{
+			name: "heartbeat fields changed",
+			mutate: func(live *v1.Node) {
+				live.Status.Allocatable = v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")}
+				live.Status.Conditions = []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			live := template.DeepCopy()
+			test.mutate(live)
+			if changed := NodeChanged(live, template); changed != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package node
+
+import (
+	"context"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/metrics"
+	cov1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// IsClusterScoped reports whether nodeSim is the NodeSimulator view of a ClusterNodeSimulator.
+func IsClusterScoped(nodeSim *simv1.NodeSimulator) bool {
+	return nodeSim.GetNamespace() == ""
+}
+
+// Persisted returns the object nodeSim is stored as, the ClusterNodeSimulator of a
+// cluster-scoped view.
+func Persisted(nodeSim *simv1.NodeSimulator) runtime.Object {
+	if !IsClusterScoped(nodeSim) {
+		return nodeSim
+	}
+	return &simv1.ClusterNodeSimulator{
+		ObjectMeta: *nodeSim.ObjectMeta.DeepCopy(),
+		Spec:       *nodeSim.Spec.DeepCopy(),
+		Status:     *nodeSim.Status.DeepCopy(),
+	}
+}
+
+// UpdateNodeSim updates the object nodeSim is stored as and refreshes nodeSim from the result.
+func (r *SimReconciler) UpdateNodeSim(ctx context.Context, nodeSim *simv1.NodeSimulator) error {
+	if !IsClusterScoped(nodeSim) {
+		return r.Update(ctx, nodeSim)
+	}
+	clusterNodeSim := Persisted(nodeSim).(*simv1.ClusterNodeSimulator)
+	if err := r.Update(ctx, clusterNodeSim); err != nil {
+		return err
+	}
+	clusterNodeSim.ObjectMeta.DeepCopyInto(&nodeSim.ObjectMeta)
+	return nil
+}
+
+// ClusterSimReconciler reconciles a ClusterNodeSimulator object with the logic of
+// SimReconciler, which it shares the settings of.
+type ClusterSimReconciler struct {
+	*SimReconciler
+}
+
+// +kubebuilder:rbac:groups=sim.k8s.io,resources=clusternodesimulators,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=sim.k8s.io,resources=clusternodesimulators/status,verbs=get;update;patch
+
+func (r *ClusterSimReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
+	var (
+		ctx            = context.Background()
+		clusterNodeSim = &simv1.ClusterNodeSimulator{}
+		err            = r.Client.Get(ctx, req.NamespacedName, clusterNodeSim)
+	)
+
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			metrics.SimulatedNodes.DeleteLabelValues("", req.Name)
+			klog.Warningf("ClusterNodeSim: %v Not Found. ", req.Name)
+		} else {
+			klog.Errorf("ClusterNodeSim: %v Error: %v ", req.Name, err)
+		}
+		return ctrl.Result{}, nil
+	}
+	return r.SyncNodeSim(ctx, clusterNodeSim.NodeSimulator())
+}
+
+func (r *ClusterSimReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Repair the fake nodes deleted or changed by others, and their leases.
+	drift := &driftHandler{r: r.SimReconciler, cluster: true}
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&simv1.ClusterNodeSimulator{}).
+		Watches(&source.Kind{Type: &v1.Node{}}, drift).
+		Watches(&source.Kind{Type: &cov1.Lease{}}, drift)
+	if r.Shard != nil {
+		// Reconcile every ClusterNodeSimulator when the nodes move between replicas.
+		rebalance := make(chan event.GenericEvent)
+		r.Shard.OnChange(func() {
+			clusterNodeSimList := &simv1.ClusterNodeSimulatorList{}
+			if err := r.Client.List(context.TODO(), clusterNodeSimList); err != nil {
+				klog.Errorf("Rebalance List ClusterNodeSim Error: %v", err)
+				return
+			}
+			for i := range clusterNodeSimList.Items {
+				rebalance <- event.GenericEvent{Meta: &clusterNodeSimList.Items[i], Object: &clusterNodeSimList.Items[i]}
+			}
+		})
+		builder = builder.Watches(&source.Channel{Source: rebalance}, &handler.EnqueueRequestForObject{})
+	}
+	return builder.Complete(r)
+}
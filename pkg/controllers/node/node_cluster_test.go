@@ -0,0 +1,85 @@
+package node
+
+import (
+	"context"
+	"testing"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestUpdateNodeSim(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := simv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		stored runtime.Object
+		// view is the NodeSimulator the reconciler works on.
+		view *simv1.NodeSimulator
+		// get reads back the stored object.
+		get func(r *SimReconciler) (metav1.Object, error)
+	}{
+		{
+			name:   "NodeSimulator",
+			stored: &simv1.NodeSimulator{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sim"}},
+			view:   &simv1.NodeSimulator{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sim"}},
+			get: func(r *SimReconciler) (metav1.Object, error) {
+				nodeSim := &simv1.NodeSimulator{}
+				err := r.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "sim"}, nodeSim)
+				return nodeSim, err
+			},
+		},
+		{
+			name:   "ClusterNodeSimulator",
+			stored: &simv1.ClusterNodeSimulator{ObjectMeta: metav1.ObjectMeta{Name: "pool"}},
+			view:   &simv1.NodeSimulator{ObjectMeta: metav1.ObjectMeta{Name: "pool"}},
+			get: func(r *SimReconciler) (metav1.Object, error) {
+				clusterNodeSim := &simv1.ClusterNodeSimulator{}
+				err := r.Get(context.TODO(), types.NamespacedName{Name: "pool"}, clusterNodeSim)
+				return clusterNodeSim, err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &SimReconciler{Client: fake.NewFakeClientWithScheme(scheme, test.stored)}
+			stored, err := test.get(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nodeSim := test.view.DeepCopy()
+			nodeSim.SetResourceVersion(stored.GetResourceVersion())
+			nodeSim.SetFinalizers([]string{NodeSimFinalizer})
+			nodeSim.Spec.Number = 3
+
+			if _, ok := Persisted(nodeSim).(*simv1.ClusterNodeSimulator); ok != IsClusterScoped(nodeSim) {
+				t.Errorf("expected the persisted object of a cluster-scoped view to be a ClusterNodeSimulator")
+			}
+			if err := r.UpdateNodeSim(context.TODO(), nodeSim); err != nil {
+				t.Fatal(err)
+			}
+
+			stored, err = test.get(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if finalizers := stored.GetFinalizers(); len(finalizers) != 1 || finalizers[0] != NodeSimFinalizer {
+				t.Errorf("expected the finalizer to be stored, got %v", finalizers)
+			}
+			if nodeSim.GetResourceVersion() != stored.GetResourceVersion() {
+				t.Errorf("expected resource version %v, got %v", stored.GetResourceVersion(), nodeSim.GetResourceVersion())
+			}
+		})
+	}
+}
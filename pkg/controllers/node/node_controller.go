@@ -18,6 +18,8 @@ package node
 import (
 	"context"
 	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/metrics"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/shard"
 	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
 	"github.com/go-logr/logr"
 	cov1 "k8s.io/api/coordination/v1"
@@ -26,10 +28,15 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"strconv"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"strings"
+	"sync/atomic"
 )
 
 // SimReconciler reconciles a NodeSimulator object
@@ -37,172 +44,346 @@ type SimReconciler struct {
 	client.Client
 	ClientSet *kubernetes.Clientset
 	Log       logr.Logger
+	Recorder  record.EventRecorder
 	Scheme    *runtime.Scheme
+	// KubeletPort is advertised in the daemon endpoints of the fake nodes.
+	KubeletPort int32
+	// Shard selects the fake nodes created, updated and removed by this replica, all
+	// of them when nil.
+	Shard *shard.Sharder
+	// Namespaces whose NodeSimulators are handled, all of them when empty.
+	Namespaces []string
+	// Workers is the number of fake nodes of a NodeSimulator synced in parallel,
+	// util.Workers when unset.
+	Workers int
 }
 
 // +kubebuilder:rbac:groups=sim.k8s.io,resources=nodesimulators,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=sim.k8s.io,resources=nodesimulators/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
 
 func (r *SimReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 	var (
-		ctx      = context.Background()
-		nodeSim  = &simv1.NodeSimulator{}
-		nodeList = &v1.NodeList{}
-		err      = r.Client.Get(ctx, req.NamespacedName, nodeSim)
+		ctx     = context.Background()
+		nodeSim = &simv1.NodeSimulator{}
+		err     = r.Client.Get(ctx, req.NamespacedName, nodeSim)
 	)
 
 	if err != nil {
 		if apierrors.IsNotFound(err) {
+			metrics.SimulatedNodes.DeleteLabelValues(req.Namespace, req.Name)
 			klog.Warningf("NodeSim: %v Not Found. ", req.NamespacedName.String())
 		} else {
 			klog.Errorf("NodeSim: %v Error: %v ", req.NamespacedName.String(), err)
 		}
 		return ctrl.Result{}, nil
 	}
+	if !util.WatchesNamespace(r.Namespaces, nodeSim.GetNamespace()) {
+		return ctrl.Result{}, nil
+	}
+	return r.SyncNodeSim(ctx, nodeSim)
+}
+
+// SyncNodeSim creates, updates and removes the fake nodes of nodeSim, which is a
+// NodeSimulator or the NodeSimulator view of a ClusterNodeSimulator.
+func (r *SimReconciler) SyncNodeSim(ctx context.Context, nodeSim *simv1.NodeSimulator) (ctrl.Result, error) {
+	var (
+		req      = ctrl.Request{NamespacedName: types.NamespacedName{Namespace: nodeSim.GetNamespace(), Name: nodeSim.GetName()}}
+		nodeList = &v1.NodeList{}
+		err      error
+	)
 
 	// Get Node List
-	err = r.Client.List(ctx, nodeList, &client.MatchingLabels{
-		ManageLabelKey: ManageLabelValue,
-		UniqueLabelKey: nodeSim.GetNamespace() + "-" + nodeSim.GetName(),
-	})
+	nodeList.Items, err = r.ListFakeNodes(ctx, nodeSim)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	metrics.SimulatedNodes.WithLabelValues(nodeSim.GetNamespace(), nodeSim.GetName()).Set(float64(len(nodeList.Items)))
 
 	if nodeSim.GetFinalizers() == nil {
 		finalizers := []string{NodeSimFinalizer}
 		nodeSim.SetFinalizers(finalizers)
-		err := r.Update(ctx, nodeSim)
+		err := r.UpdateNodeSim(ctx, nodeSim)
 		if err != nil {
 			klog.Errorf("NodeSim %v, Set Finalizers Error: %v", req.NamespacedName.String(), err)
 		}
-		// Get NodeSim
-		err = r.Client.Get(ctx, req.NamespacedName, nodeSim)
-		if err != nil {
-			klog.Errorf("Get NodeSim: %v Error: %v", req.String(), err)
-		}
 	}
 
 	if nodeSim.GetDeletionTimestamp() != nil {
+		remaining := 0
 		if nodeList.Items != nil && len(nodeList.Items) > 0 {
 			for _, node := range nodeList.Items {
+				// Left to the replica owning the node
+				if !r.Shard.Owns(node.GetName()) {
+					remaining++
+					continue
+				}
+
 				// Delete Node
-				if err := r.Client.Delete(ctx, node.DeepCopy()); err != nil {
+				if err := r.Client.Delete(ctx, node.DeepCopy()); err != nil && !apierrors.IsNotFound(err) {
 					klog.Errorf("NodeSim: %v Delete Node: %v Error: %v", req.NamespacedName.String(), node.GetName(), err)
 				}
 
 				// Delete Node Lease
 				nodeLease := &cov1.Lease{}
 				nodeLease.SetName(node.GetName())
-				nodeLease.SetNamespace("kube-node-lease")
+				nodeLease.SetNamespace(NodeLeaseNamespace)
 				if err := r.Client.Delete(ctx, nodeLease); err != nil && !apierrors.IsNotFound(err) {
 					klog.Errorf("NodeSim: %v Delete Node Lease : %v Error: %v", req.String(), node, err)
 				}
+
+				// Delete CSINode
+				if _, ok := node.GetAnnotations()[CSIDriversAnnotationKey]; ok {
+					if err := r.DeleteCSINode(ctx, node.GetName()); err != nil {
+						klog.Errorf("NodeSim: %v Delete CSINode: %v Error: %v", req.String(), node.GetName(), err)
+					}
+				}
 			}
 		}
+		if remaining > 0 {
+			return ctrl.Result{RequeueAfter: DrainRequeuePeriod}, nil
+		}
 		nodeSim.SetFinalizers(nil)
-		if err := r.Update(ctx, nodeSim); err != nil {
-			klog.Errorf("")
+		if err := r.UpdateNodeSim(ctx, nodeSim); err != nil {
+			klog.Errorf("NodeSim: %v Remove Finalizers Error: %v", req.NamespacedName.String(), err)
 		}
 
 		return ctrl.Result{}, nil
 	}
 
-	// Delete Nodes
-	if nodeList.Items != nil && len(nodeList.Items) > nodeSim.Spec.Number {
-		for i := nodeSim.Spec.Number; i < len(nodeList.Items); i++ {
+	// Split the nodes being drained from the active ones
+	activeNodes := make([]v1.Node, 0, len(nodeList.Items))
+	drainingNodes := make([]v1.Node, 0)
+	for _, node := range nodeList.Items {
+		if IsDraining(&node) {
+			drainingNodes = append(drainingNodes, node)
+		} else {
+			activeNodes = append(activeNodes, node)
+		}
+	}
 
-			//Delete Node
-			nodeName := nodeSim.GetNamespace() + "-" + nodeSim.GetName() + "-" + strconv.Itoa(i)
-			node := &v1.Node{}
-			node.SetName(nodeName)
-			if err := r.Client.Delete(ctx, node); err != nil && !apierrors.IsNotFound(err) {
-				klog.Errorf("NodeSim: %v Delete Node: %v Error: %v", req.String(), node, err)
+	// Scale Down
+	if len(activeNodes) > nodeSim.Spec.Number {
+		podCount, err := r.managedPodCount()
+		if err != nil {
+			klog.Errorf("NodeSim: %v Count Pods Error: %v", req.String(), err)
+			return ctrl.Result{}, err
+		}
+		victims := SelectScaleDownNodes(nodeSim, activeNodes, podCount, len(activeNodes)-nodeSim.Spec.Number)
+		removed := make(map[string]bool, len(victims))
+		for _, node := range victims {
+			removed[node.GetName()] = true
+			if !r.Shard.Owns(node.GetName()) {
+				continue
 			}
-			// Delete Node Lease
-			nodeLease := &cov1.Lease{}
-			nodeLease.SetName(nodeName)
-			nodeLease.SetNamespace("kube-node-lease")
-			if err := r.Client.Delete(ctx, nodeLease); err != nil && !apierrors.IsNotFound(err) {
-				klog.Errorf("NodeSim: %v Delete Node Lease : %v Error: %v", req.String(), node, err)
+			if nodeSim.Spec.ScaleDown != nil && nodeSim.Spec.ScaleDown.Drain {
+				if err := r.StartDrain(ctx, node.DeepCopy()); err != nil {
+					klog.Errorf("NodeSim: %v Cordon Node: %v Error: %v", req.String(), node.GetName(), err)
+					continue
+				}
+				r.Recorder.Eventf(&node, v1.EventTypeNormal, DrainingEventReason, "Node %v is cordoned and drained for scale-down", node.GetName())
+				drainingNodes = append(drainingNodes, node)
+			} else {
+				r.DeleteFakeNode(ctx, nodeSim, node.GetName())
 			}
 		}
+		keepNodes := make([]v1.Node, 0, nodeSim.Spec.Number)
+		for _, node := range activeNodes {
+			if !removed[node.GetName()] {
+				keepNodes = append(keepNodes, node)
+			}
+		}
+		activeNodes = keepNodes
 	}
 
-	r.SyncFakeNode(ctx, nodeSim)
+	// Drain Nodes
+	result := ctrl.Result{}
+	for _, node := range drainingNodes {
+		if !r.Shard.Owns(node.GetName()) {
+			continue
+		}
+		if r.DrainNode(nodeSim, &node) {
+			r.DeleteFakeNode(ctx, nodeSim, node.GetName())
+		} else {
+			result.RequeueAfter = DrainRequeuePeriod
+		}
+	}
+
+	// Retry the nodes not created yet, which nothing else would trigger
+	if !r.SyncFakeNode(ctx, nodeSim, activeNodes, nodeList.Items) && result.RequeueAfter == 0 {
+		result.RequeueAfter = CreateRequeuePeriod
+	}
 
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
-func (r *SimReconciler) SyncFakeNode(ctx context.Context, nodeSim *simv1.NodeSimulator) {
+// SyncFakeNode keeps activeNodes in sync with the template and creates new nodes
+// until nodeSim.Spec.Number is reached, skipping every name used in existingNodes.
+// Only the nodes that differ from the template are patched, and the missing ones are
+// created in batches whose progress is reported in the status of nodeSim. It returns
+// false while nodes are missing: when some creates failed, or when the replica
+// reporting the status still waits for the nodes of the other replicas.
+func (r *SimReconciler) SyncFakeNode(ctx context.Context, nodeSim *simv1.NodeSimulator, activeNodes, existingNodes []v1.Node) bool {
 	// Filter
 	if nodeSim.Spec.Number <= 0 {
-		return
+		r.SyncStatus(ctx, nodeSim, 0)
+		return true
 	}
 
 	nodeTemplate, err := GenNode(nodeSim)
 	if err != nil {
-		return
+		return true
 	}
-
-	nodeList := make([]*v1.Node, 0)
-	// Gen NodeList
-	for i := 0; i < nodeSim.Spec.Number; i++ {
-		vnode := nodeTemplate.DeepCopy()
-		fakeName := nodeSim.GetNamespace() + "-" + nodeSim.GetName() + "-" + strconv.Itoa(i)
-		vnode.SetName(fakeName)
-		nodeList = append(nodeList, vnode)
+	nodeTemplate.Status.DaemonEndpoints.KubeletEndpoint.Port = r.KubeletPort
+	templateHash, err := TemplateHash(nodeTemplate)
+	if err != nil {
+		return true
 	}
+	nodeTemplate.Annotations[TemplateHashAnnotationKey] = templateHash
 
-	SyncNode := func(ctx context.Context, node *v1.Node) {
-		fakeNode := &v1.Node{}
-
-		if node.Status.Addresses == nil {
-			node.Status.Addresses = make([]v1.NodeAddress, 0)
-		}
-
-		node.Status.Addresses = append(node.Status.Addresses, v1.NodeAddress{
+	genNode := func(name string) *v1.Node {
+		vnode := nodeTemplate.DeepCopy()
+		vnode.SetName(name)
+		vnode.Spec.ProviderID = ProviderID(name)
+		vnode.Status.Addresses = append(append(make([]v1.NodeAddress, 0), nodeTemplate.Status.Addresses...), v1.NodeAddress{
 			Type:    v1.NodeHostName,
-			Address: node.GetName(),
+			Address: name,
 		})
+		return vnode
+	}
 
-		err := r.Client.Get(ctx, types.NamespacedName{
-			Name:      node.GetName(),
-			Namespace: node.GetNamespace(),
-		}, fakeNode)
-		if err != nil && apierrors.IsNotFound(err) {
-			if err := r.Client.Create(ctx, node); err != nil {
-				klog.Errorf("NodeSim: %v/%v Create Node: %v Error: %v ", nodeSim.GetNamespace(), nodeSim.GetName(), node.GetName(), err)
-			}
-		} else {
-			specOps := []util.Ops{
-				{
-					Op:    "replace",
-					Path:  "/spec",
-					Value: node.Spec,
-				},
+	usedNames := make(map[string]bool, len(existingNodes))
+	for _, node := range existingNodes {
+		usedNames[node.GetName()] = true
+	}
+	// Diff the active nodes against the template
+	updates := make([]*v1.Node, 0)
+	liveNodes := make(map[string]*v1.Node, len(activeNodes))
+	for i := range activeNodes {
+		if i >= nodeSim.Spec.Number {
+			break
+		}
+		live := &activeNodes[i]
+		if !r.Shard.Owns(live.GetName()) {
+			continue
+		}
+		if nodeSim.Spec.DriftPolicy != simv1.DriftPolicyAdopt {
+			r.HealLease(ctx, nodeSim, live)
+		}
+		if len(nodeSim.Spec.CSIDrivers) > 0 {
+			// Recreate the CSINode if it was deleted, or restore its drivers
+			if err := r.SyncCSINode(ctx, live, nodeSim.Spec.CSIDrivers); err != nil {
+				klog.Errorf("NodeSim: %v/%v Sync CSINode: %v Error: %v ", nodeSim.GetNamespace(), nodeSim.GetName(), live.GetName(), err)
 			}
-
-			if err := r.Client.Patch(ctx, node, &util.Patch{PatchOps: specOps}); err != nil {
-				klog.Errorf("NodeSim: %v/%v Patch Node: %v Error: %v ", nodeSim.GetNamespace(), nodeSim.GetName(), node.GetName(), err)
+		}
+		vnode := genNode(live.GetName())
+		if !NodeChanged(live, vnode) {
+			continue
+		}
+		// Changed by others since the last sync from this template
+		if nodeSim.Spec.DriftPolicy == simv1.DriftPolicyAdopt && live.GetAnnotations()[TemplateHashAnnotationKey] == templateHash {
+			continue
+		}
+		liveNodes[live.GetName()] = live
+		updates = append(updates, vnode)
+	}
+	creates := make([]*v1.Node, 0)
+	conflicts := make([]string, 0)
+	current := len(activeNodes)
+	if current > nodeSim.Spec.Number {
+		current = nodeSim.Spec.Number
+	}
+	for i := 0; current+len(creates) < nodeSim.Spec.Number; i++ {
+		fakeName := GenNodeName(nodeSim, i)
+		if usedNames[fakeName] {
+			continue
+		}
+		usedNames[fakeName] = true
+		// Never take over a node of another owner that has the same name
+		existing := &v1.Node{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: fakeName}, existing); err == nil {
+			if IsForeign(existing, nodeSim) {
+				conflicts = append(conflicts, fakeName)
 			}
+			continue
+		}
+		creates = append(creates, genNode(fakeName))
+	}
 
-			newNode := fakeNode.DeepCopy()
-			newNode.Status.Allocatable = nodeTemplate.Status.Allocatable
-			newNode.Status.Capacity = nodeTemplate.Status.Capacity
-			newNode.Status.Addresses = node.Status.Addresses
-			_, _, err := util.PatchNodeStatus(r.ClientSet.CoreV1(), types.NodeName(node.GetName()), fakeNode, newNode)
-			if err != nil {
-				klog.Errorf("Patch Node: %v Error: %v", newNode.GetName(), err)
-			}
+	if len(conflicts) > 0 {
+		names := conflicts
+		if len(names) > 10 {
+			names = append(names[:10:10], "...")
 		}
+		r.Recorder.Eventf(nodeSim, v1.EventTypeWarning, OwnershipConflictEventReason,
+			"Skipped %d node names owned by others: %v", len(conflicts), strings.Join(names, ", "))
 	}
 
-	util.ParallelizeSyncNode(ctx, 5, nodeList, SyncNode)
+	workers := r.Workers
+	if workers <= 0 {
+		workers = util.Workers
+	}
+	util.ParallelizeSyncNode(ctx, workers, updates, func(ctx context.Context, node *v1.Node) {
+		r.PatchFakeNode(ctx, nodeSim, liveNodes[node.GetName()], node)
+	})
+
+	// Create the missing nodes in batches, nodes owned by other replicas are
+	// created by them.
+	owned := make([]*v1.Node, 0, len(creates))
+	for _, node := range creates {
+		if r.Shard.Owns(node.GetName()) {
+			owned = append(owned, node)
+		}
+	}
+	created := current
+	r.SyncStatus(ctx, nodeSim, created)
+	failed := 0
+	for start := 0; start < len(owned); start += CreateBatchSize {
+		end := start + CreateBatchSize
+		if end > len(owned) {
+			end = len(owned)
+		}
+		var count int32
+		util.ParallelizeSyncNode(ctx, workers, owned[start:end], func(ctx context.Context, node *v1.Node) {
+			if r.CreateFakeNode(ctx, nodeSim, node) {
+				atomic.AddInt32(&count, 1)
+			}
+		})
+		created += int(count)
+		failed += end - start - int(count)
+		r.SyncStatus(ctx, nodeSim, created)
+	}
+	if failed > 0 {
+		return false
+	}
+	return !r.ReportsStatus(nodeSim) || created >= nodeSim.Spec.Number
 }
 
 func (r *SimReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// Repair the fake nodes deleted or changed by others, and their leases.
+	drift := &driftHandler{r: r}
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&simv1.NodeSimulator{}).
-		Complete(r)
+		Watches(&source.Kind{Type: &v1.Node{}}, drift).
+		Watches(&source.Kind{Type: &cov1.Lease{}}, drift)
+	if r.Shard != nil {
+		// Reconcile every NodeSimulator when the nodes move between replicas.
+		rebalance := make(chan event.GenericEvent)
+		r.Shard.OnChange(func() {
+			nodeSimList := &simv1.NodeSimulatorList{}
+			if err := r.Client.List(context.TODO(), nodeSimList); err != nil {
+				klog.Errorf("Rebalance List NodeSim Error: %v", err)
+				return
+			}
+			for i := range nodeSimList.Items {
+				rebalance <- event.GenericEvent{Meta: &nodeSimList.Items[i], Object: &nodeSimList.Items[i]}
+			}
+		})
+		builder = builder.Watches(&source.Channel{Source: rebalance}, &handler.EnqueueRequestForObject{})
+	}
+	return builder.Complete(r)
 }
@@ -0,0 +1,97 @@
+package node
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=csinodes,verbs=get;list;watch;create;update;patch;delete
+
+// CSINodeGVK is handled as an unstructured object, the client-go in use only has
+// the storage.k8s.io/v1beta1 CSINode.
+var CSINodeGVK = schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "CSINode"}
+
+// GenCSINodeDrivers returns the drivers of the CSINode spec of a node.
+func GenCSINodeDrivers(nodeName string, drivers []simv1.CSIDriverSpec) []interface{} {
+	csiDrivers := make([]interface{}, 0, len(drivers))
+	for _, driver := range drivers {
+		nodeID := strings.Replace(driver.NodeID, NodeNamePlaceholder, nodeName, -1)
+		if nodeID == "" {
+			nodeID = nodeName
+		}
+		csiDriver := map[string]interface{}{
+			"name":   driver.Name,
+			"nodeID": nodeID,
+		}
+		if len(driver.TopologyKeys) > 0 {
+			keys := make([]interface{}, 0, len(driver.TopologyKeys))
+			for _, key := range driver.TopologyKeys {
+				keys = append(keys, key)
+			}
+			csiDriver["topologyKeys"] = keys
+		}
+		if driver.MaxVolumes != nil {
+			csiDriver["allocatable"] = map[string]interface{}{"count": *driver.MaxVolumes}
+		}
+		csiDrivers = append(csiDrivers, csiDriver)
+	}
+	return csiDrivers
+}
+
+// SyncCSINode creates or updates the CSINode of a fake node from its CSI drivers, like
+// the kubelet does when the drivers register, and deletes it once the node has no
+// drivers left. The CSINode is owned by the node and removed with it.
+func (r *SimReconciler) SyncCSINode(ctx context.Context, node *v1.Node, drivers []simv1.CSIDriverSpec) error {
+	if len(drivers) == 0 {
+		return r.DeleteCSINode(ctx, node.GetName())
+	}
+	csiDrivers := GenCSINodeDrivers(node.GetName(), drivers)
+
+	csiNode := &unstructured.Unstructured{}
+	csiNode.SetGroupVersionKind(CSINodeGVK)
+	err := r.Client.Get(ctx, types.NamespacedName{Name: node.GetName()}, csiNode)
+	if err != nil && apierrors.IsNotFound(err) {
+		csiNode = &unstructured.Unstructured{}
+		csiNode.SetGroupVersionKind(CSINodeGVK)
+		csiNode.SetName(node.GetName())
+		csiNode.SetLabels(map[string]string{ManageLabelKey: ManageLabelValue})
+		csiNode.SetOwnerReferences([]metav1.OwnerReference{
+			*metav1.NewControllerRef(node, v1.SchemeGroupVersion.WithKind("Node")),
+		})
+		csiNode.Object["spec"] = map[string]interface{}{
+			"drivers": csiDrivers,
+		}
+		return r.Client.Create(ctx, csiNode)
+	} else if err != nil {
+		return err
+	}
+
+	current, _, _ := unstructured.NestedSlice(csiNode.Object, "spec", "drivers")
+	if reflect.DeepEqual(current, csiDrivers) {
+		return nil
+	}
+	if err := unstructured.SetNestedSlice(csiNode.Object, csiDrivers, "spec", "drivers"); err != nil {
+		return err
+	}
+	return r.Client.Update(ctx, csiNode)
+}
+
+// DeleteCSINode deletes the CSINode of a fake node.
+func (r *SimReconciler) DeleteCSINode(ctx context.Context, nodeName string) error {
+	csiNode := &unstructured.Unstructured{}
+	csiNode.SetGroupVersionKind(CSINodeGVK)
+	csiNode.SetName(nodeName)
+	if err := r.Client.Delete(ctx, csiNode); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
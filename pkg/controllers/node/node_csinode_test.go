@@ -0,0 +1,127 @@
+package node
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestGenCSINodeDrivers(t *testing.T) {
+	maxVolumes := int64(25)
+
+	tests := []struct {
+		name     string
+		drivers  []simv1.CSIDriverSpec
+		expected []interface{}
+	}{
+		{
+			name:     "no drivers",
+			expected: []interface{}{},
+		},
+		{
+			name:    "node name placeholder replaced",
+			drivers: []simv1.CSIDriverSpec{{Name: "ebs.csi.aws.com", NodeID: "i-" + NodeNamePlaceholder}},
+			expected: []interface{}{
+				map[string]interface{}{"name": "ebs.csi.aws.com", "nodeID": "i-fake-0"},
+			},
+		},
+		{
+			name:    "node name without node ID",
+			drivers: []simv1.CSIDriverSpec{{Name: "ebs.csi.aws.com"}},
+			expected: []interface{}{
+				map[string]interface{}{"name": "ebs.csi.aws.com", "nodeID": "fake-0"},
+			},
+		},
+		{
+			name: "topology keys and allocatable count",
+			drivers: []simv1.CSIDriverSpec{{
+				Name:         "ebs.csi.aws.com",
+				NodeID:       "ebs",
+				TopologyKeys: []string{"topology.ebs.csi.aws.com/zone"},
+				MaxVolumes:   &maxVolumes,
+			}},
+			expected: []interface{}{
+				map[string]interface{}{
+					"name":         "ebs.csi.aws.com",
+					"nodeID":       "ebs",
+					"topologyKeys": []interface{}{"topology.ebs.csi.aws.com/zone"},
+					"allocatable":  map[string]interface{}{"count": int64(25)},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if drivers := GenCSINodeDrivers("fake-0", test.drivers); !reflect.DeepEqual(drivers, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, drivers)
+			}
+		})
+	}
+}
+
+func TestSyncCSINode(t *testing.T) {
+	maxVolumes := int64(25)
+	drivers := []simv1.CSIDriverSpec{{Name: "ebs.csi.aws.com", TopologyKeys: []string{"zone"}, MaxVolumes: &maxVolumes}}
+	fakeNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "fake-0", UID: "uid"}}
+	r := &SimReconciler{Client: fake.NewFakeClientWithScheme(clientgoscheme.Scheme)}
+	get := func() (*unstructured.Unstructured, error) {
+		csiNode := &unstructured.Unstructured{}
+		csiNode.SetGroupVersionKind(CSINodeGVK)
+		err := r.Client.Get(context.TODO(), types.NamespacedName{Name: "fake-0"}, csiNode)
+		return csiNode, err
+	}
+
+	tests := []struct {
+		name    string
+		drivers []simv1.CSIDriverSpec
+		// updated is whether the CSINode is expected to be written.
+		updated bool
+	}{
+		{name: "created", drivers: drivers, updated: true},
+		{name: "unchanged", drivers: drivers},
+		{name: "driver changed", drivers: []simv1.CSIDriverSpec{{Name: "ebs.csi.aws.com"}}, updated: true},
+		{name: "unchanged again", drivers: []simv1.CSIDriverSpec{{Name: "ebs.csi.aws.com"}}},
+		{name: "deleted without drivers", updated: true},
+	}
+
+	resourceVersion := ""
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := r.SyncCSINode(context.TODO(), fakeNode, test.drivers); err != nil {
+				t.Fatal(err)
+			}
+			csiNode, err := get()
+			if len(test.drivers) == 0 {
+				if !apierrors.IsNotFound(err) {
+					t.Fatalf("expected the CSINode to be deleted, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if updated := csiNode.GetResourceVersion() != resourceVersion; updated != test.updated {
+				t.Errorf("expected updated %v, got resource version %v after %v", test.updated, csiNode.GetResourceVersion(), resourceVersion)
+			}
+			resourceVersion = csiNode.GetResourceVersion()
+
+			current, _, _ := unstructured.NestedSlice(csiNode.Object, "spec", "drivers")
+			if expected := GenCSINodeDrivers("fake-0", test.drivers); !reflect.DeepEqual(current, expected) {
+				t.Errorf("expected drivers %v, got %v", expected, current)
+			}
+			if owners := csiNode.GetOwnerReferences(); len(owners) != 1 || owners[0].UID != fakeNode.GetUID() {
+				t.Errorf("expected the node as owner, got %v", owners)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package node
+
+import (
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Device is one simulated device of a node.
+type Device struct {
+	ID           string             `json:"id"`
+	ResourceName v1.ResourceName    `json:"resourceName"`
+	Model        string             `json:"model,omitempty"`
+	Memory       *resource.Quantity `json:"memory,omitempty"`
+	Healthy      bool               `json:"healthy"`
+	// Spec the device was generated from.
+	Spec *simv1.DeviceSpec `json:"-"`
+}
+
+// IsDRA reports whether the device is published in ResourceSlices rather than as
+// an extended resource.
+func (d *Device) IsDRA() bool {
+	return d.Spec != nil && d.Spec.DRADriver != ""
+}
+
+// PodDevices maps container names to the device IDs allocated to them, per resource.
+type PodDevices map[string]map[v1.ResourceName][]string
+
+// DeviceID returns the ID of the index-th device of spec, such as gpu-0 for nvidia.com/gpu.
+func DeviceID(spec *simv1.DeviceSpec, index int) string {
+	return path.Base(string(spec.ResourceName)) + "-" + strconv.Itoa(index)
+}
+
+// GenDeviceCapacity sets the capacity of the devices of nodesim on the node.
+func GenDeviceCapacity(nodesim *simv1.NodeSimulator, node *v1.Node) error {
+	if len(nodesim.Spec.Devices) == 0 {
+		return nil
+	}
+	devices, err := json.Marshal(nodesim.Spec.Devices)
+	if err != nil {
+		return err
+	}
+	node.Annotations[DevicesAnnotationKey] = string(devices)
+
+	capacity := node.Status.Capacity.DeepCopy()
+	if capacity == nil {
+		capacity = v1.ResourceList{}
+	}
+	for _, spec := range nodesim.Spec.Devices {
+		if spec.DRADriver != "" {
+			continue
+		}
+		capacity[spec.ResourceName] = *resource.NewQuantity(int64(spec.Count), resource.DecimalSI)
+	}
+	node.Status.Capacity = capacity
+	node.Status.Allocatable = capacity
+	return nil
+}
+
+// NodeDevices returns the devices of a fake node, unhealthy when listed in its
+// UnhealthyDevicesAnnotationKey annotation.
+func NodeDevices(node *v1.Node) ([]Device, error) {
+	value, ok := node.GetAnnotations()[DevicesAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	specs := make([]simv1.DeviceSpec, 0)
+	if err := json.Unmarshal([]byte(value), &specs); err != nil {
+		return nil, err
+	}
+
+	unhealthy := make(map[string]bool)
+	for _, id := range strings.Split(node.GetAnnotations()[UnhealthyDevicesAnnotationKey], ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			unhealthy[id] = true
+		}
+	}
+
+	devices := make([]Device, 0)
+	for i := range specs {
+		for index := 0; index < specs[i].Count; index++ {
+			id := DeviceID(&specs[i], index)
+			devices = append(devices, Device{
+				ID:           id,
+				ResourceName: specs[i].ResourceName,
+				Model:        specs[i].Model,
+				Memory:       specs[i].Memory,
+				Healthy:      !unhealthy[id],
+				Spec:         &specs[i],
+			})
+		}
+	}
+	return devices, nil
+}
+
+// UnhealthyDeviceCount counts the unhealthy devices per resource.
+func UnhealthyDeviceCount(devices []Device) v1.ResourceList {
+	count := make(map[v1.ResourceName]int64)
+	for _, device := range devices {
+		if !device.Healthy && !device.IsDRA() {
+			count[device.ResourceName]++
+		}
+	}
+	list := v1.ResourceList{}
+	for name, value := range count {
+		list[name] = *resource.NewQuantity(value, resource.DecimalSI)
+	}
+	return list
+}
+
+// GetPodDevices returns the devices allocated to a pod, nil if none were.
+func GetPodDevices(pod *v1.Pod) (PodDevices, error) {
+	value, ok := pod.GetAnnotations()[AllocatedDevicesAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	allocated := PodDevices{}
+	if err := json.Unmarshal([]byte(value), &allocated); err != nil {
+		return nil, err
+	}
+	return allocated, nil
+}
+
+// AllocateDevices picks healthy devices not used by the pods in usedBy for every
+// container of pod requesting some, lowest IDs first. It returns false when the
+// node does not have enough free devices.
+func AllocateDevices(devices []Device, pod *v1.Pod, usedBy []v1.Pod) (PodDevices, bool) {
+	used := make(map[string]bool)
+	for i := range usedBy {
+		allocated, err := GetPodDevices(&usedBy[i])
+		if err != nil {
+			continue
+		}
+		for _, resources := range allocated {
+			for _, ids := range resources {
+				for _, id := range ids {
+					used[id] = true
+				}
+			}
+		}
+	}
+
+	free := make(map[v1.ResourceName][]string)
+	for _, device := range devices {
+		if device.Healthy && !device.IsDRA() && !used[device.ID] {
+			free[device.ResourceName] = append(free[device.ResourceName], device.ID)
+		}
+	}
+
+	allocated := PodDevices{}
+	for _, container := range pod.Spec.Containers {
+		names := make([]string, 0)
+		for name := range container.Resources.Limits {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			resourceName := v1.ResourceName(name)
+			ids, ok := free[resourceName]
+			if !ok && !isDeviceResource(devices, resourceName) {
+				continue
+			}
+			limit := container.Resources.Limits[resourceName]
+			count := int(limit.Value())
+			if count == 0 {
+				continue
+			}
+			if count > len(ids) {
+				return nil, false
+			}
+			if allocated[container.Name] == nil {
+				allocated[container.Name] = make(map[v1.ResourceName][]string)
+			}
+			allocated[container.Name][resourceName] = ids[:count]
+			free[resourceName] = ids[count:]
+		}
+	}
+	return allocated, true
+}
+
+func isDeviceResource(devices []Device, name v1.ResourceName) bool {
+	for _, device := range devices {
+		if device.ResourceName == name && !device.IsDRA() {
+			return true
+		}
+	}
+	return false
+}
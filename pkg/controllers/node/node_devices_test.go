@@ -0,0 +1,109 @@
+package node
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAllocateDevices(t *testing.T) {
+	const gpu = v1.ResourceName("nvidia.com/gpu")
+	const fpga = v1.ResourceName("xilinx.com/fpga")
+	newDevices := func(unhealthy string) []Device {
+		specs, _ := json.Marshal([]simv1.DeviceSpec{
+			{ResourceName: gpu, Count: 4},
+			{ResourceName: fpga, Count: 1},
+			{ResourceName: "example.com/dra", Count: 2, DRADriver: "dra.example.com"},
+		})
+		devices, _ := NodeDevices(&v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			DevicesAnnotationKey:          string(specs),
+			UnhealthyDevicesAnnotationKey: unhealthy,
+		}}})
+		return devices
+	}
+	newPod := func(limits ...v1.ResourceList) *v1.Pod {
+		pod := &v1.Pod{}
+		for i, limit := range limits {
+			pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{
+				Name:      "c" + string(rune('0'+i)),
+				Resources: v1.ResourceRequirements{Limits: limit},
+			})
+		}
+		return pod
+	}
+	usingPod := func(allocated PodDevices) v1.Pod {
+		data, _ := json.Marshal(allocated)
+		return v1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			AllocatedDevicesAnnotationKey: string(data),
+		}}}
+	}
+	limit := func(name v1.ResourceName, count string) v1.ResourceList {
+		return v1.ResourceList{name: resource.MustParse(count)}
+	}
+
+	tests := []struct {
+		name      string
+		unhealthy string
+		pod       *v1.Pod
+		usedBy    []v1.Pod
+		expected  PodDevices
+		ok        bool
+	}{
+		{
+			name:     "lowest IDs first",
+			pod:      newPod(limit(gpu, "2")),
+			expected: PodDevices{"c0": {gpu: {"gpu-0", "gpu-1"}}},
+			ok:       true,
+		},
+		{
+			name: "containers get distinct devices",
+			pod:  newPod(limit(gpu, "1"), v1.ResourceList{gpu: resource.MustParse("2"), fpga: resource.MustParse("1")}),
+			expected: PodDevices{
+				"c0": {gpu: {"gpu-0"}},
+				"c1": {gpu: {"gpu-1", "gpu-2"}, fpga: {"fpga-0"}},
+			},
+			ok: true,
+		},
+		{
+			name:      "unhealthy and used devices skipped",
+			unhealthy: "gpu-0",
+			pod:       newPod(limit(gpu, "2")),
+			usedBy:    []v1.Pod{usingPod(PodDevices{"c0": {gpu: {"gpu-1"}}})},
+			expected:  PodDevices{"c0": {gpu: {"gpu-2", "gpu-3"}}},
+			ok:        true,
+		},
+		{
+			name:   "not enough free devices",
+			pod:    newPod(limit(gpu, "2")),
+			usedBy: []v1.Pod{usingPod(PodDevices{"c0": {gpu: {"gpu-0", "gpu-1", "gpu-2"}}})},
+		},
+		{
+			name:      "all devices unhealthy",
+			unhealthy: "fpga-0",
+			pod:       newPod(limit(fpga, "1")),
+		},
+		{
+			name:     "other resources and DRA devices ignored",
+			pod:      newPod(v1.ResourceList{v1.ResourceCPU: resource.MustParse("1"), "example.com/dra": resource.MustParse("1")}),
+			expected: PodDevices{},
+			ok:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			allocated, ok := AllocateDevices(newDevices(test.unhealthy), test.pod, test.usedBy)
+			if ok != test.ok {
+				t.Fatalf("expected ok %v, got %v", test.ok, ok)
+			}
+			if !reflect.DeepEqual(allocated, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, allocated)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package node
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// +kubebuilder:rbac:groups=resource.k8s.io,resources=resourceslices,verbs=get;list;watch;create;update;patch;delete
+
+// The client-go in use predates resource.k8s.io, ResourceSlices and ResourceClaims
+// are handled as unstructured objects.
+var (
+	ResourceSliceGVK = schema.GroupVersionKind{Group: "resource.k8s.io", Version: "v1", Kind: "ResourceSlice"}
+	ResourceClaimGVK = schema.GroupVersionKind{Group: "resource.k8s.io", Version: "v1", Kind: "ResourceClaim"}
+)
+
+// ResourceSliceName returns the name of the ResourceSlice of a driver on a node.
+func ResourceSliceName(nodeName, driver string) string {
+	return nodeName + "-" + driver
+}
+
+// DRADrivers returns the DRA drivers of the devices and their healthy devices.
+func DRADrivers(devices []Device) map[string][]Device {
+	drivers := make(map[string][]Device)
+	for _, device := range devices {
+		if !device.IsDRA() {
+			continue
+		}
+		if _, ok := drivers[device.Spec.DRADriver]; !ok {
+			drivers[device.Spec.DRADriver] = make([]Device, 0)
+		}
+		if device.Healthy {
+			drivers[device.Spec.DRADriver] = append(drivers[device.Spec.DRADriver], device)
+		}
+	}
+	return drivers
+}
+
+// GenResourceSliceDevices returns the devices of a ResourceSlice spec. The pool of
+// a fake node is named after the node.
+func GenResourceSliceDevices(devices []Device) []interface{} {
+	sliceDevices := make([]interface{}, 0, len(devices))
+	for _, device := range devices {
+		attributes := map[string]interface{}{
+			"resourceName": map[string]interface{}{"string": string(device.ResourceName)},
+		}
+		if device.Model != "" {
+			attributes["model"] = map[string]interface{}{"string": device.Model}
+		}
+		sliceDevice := map[string]interface{}{
+			"name":       device.ID,
+			"attributes": attributes,
+		}
+		if device.Memory != nil {
+			sliceDevice["capacity"] = map[string]interface{}{
+				"memory": map[string]interface{}{"value": device.Memory.String()},
+			}
+		}
+		sliceDevices = append(sliceDevices, sliceDevice)
+	}
+	return sliceDevices
+}
+
+// SyncResourceSlices publishes one ResourceSlice per DRA driver of a fake node, like
+// the kubelet plugin of the driver would. Unhealthy devices are left out, and the
+// generation of the pool is bumped whenever its devices change. The slices are owned
+// by the node and removed with it.
+func (n *Updater) SyncResourceSlices(ctx context.Context, node *v1.Node) error {
+	devices, err := NodeDevices(node)
+	if err != nil {
+		return err
+	}
+	drivers := DRADrivers(devices)
+	names := make([]string, 0, len(drivers))
+	for driver := range drivers {
+		names = append(names, driver)
+	}
+	sort.Strings(names)
+
+	for _, driver := range names {
+		sliceDevices := GenResourceSliceDevices(drivers[driver])
+
+		slice := &unstructured.Unstructured{}
+		slice.SetGroupVersionKind(ResourceSliceGVK)
+		err := n.Client.Get(ctx, types.NamespacedName{Name: ResourceSliceName(node.GetName(), driver)}, slice)
+		if err != nil && apierrors.IsNotFound(err) {
+			slice = &unstructured.Unstructured{}
+			slice.SetGroupVersionKind(ResourceSliceGVK)
+			slice.SetName(ResourceSliceName(node.GetName(), driver))
+			slice.SetLabels(map[string]string{ManageLabelKey: ManageLabelValue})
+			slice.SetOwnerReferences([]metav1.OwnerReference{
+				*metav1.NewControllerRef(node, v1.SchemeGroupVersion.WithKind("Node")),
+			})
+			slice.Object["spec"] = map[string]interface{}{
+				"driver":   driver,
+				"nodeName": node.GetName(),
+				"pool": map[string]interface{}{
+					"name":               node.GetName(),
+					"generation":         int64(1),
+					"resourceSliceCount": int64(1),
+				},
+				"devices": sliceDevices,
+			}
+			if err := n.Client.Create(ctx, slice); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		current, _, _ := unstructured.NestedSlice(slice.Object, "spec", "devices")
+		if (len(current) == 0 && len(sliceDevices) == 0) || reflect.DeepEqual(current, sliceDevices) {
+			continue
+		}
+		generation, _, _ := unstructured.NestedInt64(slice.Object, "spec", "pool", "generation")
+		if err := unstructured.SetNestedField(slice.Object, generation+1, "spec", "pool", "generation"); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedSlice(slice.Object, sliceDevices, "spec", "devices"); err != nil {
+			return err
+		}
+		if err := n.Client.Update(ctx, slice); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package node
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDRADrivers(t *testing.T) {
+	memory := resource.MustParse("80Gi")
+	specs, _ := json.Marshal([]simv1.DeviceSpec{
+		{ResourceName: "nvidia.com/gpu", Count: 2, Model: "A100", Memory: &memory, DRADriver: "gpu.nvidia.com"},
+		{ResourceName: "example.com/nic", Count: 1, DRADriver: "nic.example.com"},
+		{ResourceName: "xilinx.com/fpga", Count: 1},
+	})
+	newDevices := func(unhealthy string) []Device {
+		devices, err := NodeDevices(&v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			DevicesAnnotationKey:          string(specs),
+			UnhealthyDevicesAnnotationKey: unhealthy,
+		}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return devices
+	}
+	ids := func(drivers map[string][]Device) map[string][]string {
+		result := make(map[string][]string)
+		for driver, devices := range drivers {
+			result[driver] = []string{}
+			for _, device := range devices {
+				result[driver] = append(result[driver], device.ID)
+			}
+		}
+		return result
+	}
+
+	tests := []struct {
+		name      string
+		unhealthy string
+		expected  map[string][]string
+	}{
+		{
+			name:     "healthy devices per driver",
+			expected: map[string][]string{"gpu.nvidia.com": {"gpu-0", "gpu-1"}, "nic.example.com": {"nic-0"}},
+		},
+		{
+			name:      "unhealthy devices left out, their driver kept",
+			unhealthy: "gpu-1,nic-0",
+			expected:  map[string][]string{"gpu.nvidia.com": {"gpu-0"}, "nic.example.com": {}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if drivers := ids(DRADrivers(newDevices(test.unhealthy))); !reflect.DeepEqual(drivers, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, drivers)
+			}
+		})
+	}
+
+	devices := GenResourceSliceDevices(DRADrivers(newDevices(""))["gpu.nvidia.com"])
+	expected := []interface{}{
+		map[string]interface{}{
+			"name": "gpu-0",
+			"attributes": map[string]interface{}{
+				"resourceName": map[string]interface{}{"string": "nvidia.com/gpu"},
+				"model":        map[string]interface{}{"string": "A100"},
+			},
+			"capacity": map[string]interface{}{"memory": map[string]interface{}{"value": "80Gi"}},
+		},
+		map[string]interface{}{
+			"name": "gpu-1",
+			"attributes": map[string]interface{}{
+				"resourceName": map[string]interface{}{"string": "nvidia.com/gpu"},
+				"model":        map[string]interface{}{"string": "A100"},
+			},
+			"capacity": map[string]interface{}{"memory": map[string]interface{}{"value": "80Gi"}},
+		},
+	}
+	if !reflect.DeepEqual(devices, expected) {
+		t.Errorf("expected slice devices %v, got %v", expected, devices)
+	}
+}
@@ -0,0 +1,169 @@
+package node
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OwnerID returns the value of the UniqueLabelKey label of the nodes of nodesim, a
+// hash of its namespace and name. Unlike the "namespace-name" value of older
+// releases it can't be shared by two NodeSimulators, such as a-b/c and a/b-c.
+func OwnerID(nodesim *simv1.NodeSimulator) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(OwnerKey(nodesim))))[:32]
+}
+
+// LegacyOwnerID returns the value of the UniqueLabelKey label of the nodes created
+// by older releases.
+func LegacyOwnerID(nodesim *simv1.NodeSimulator) string {
+	if IsClusterScoped(nodesim) {
+		return ""
+	}
+	return nodesim.GetNamespace() + "-" + nodesim.GetName()
+}
+
+// OwnerKey returns the value of the OwnerAnnotationKey annotation of the nodes of
+// nodesim, the name alone for a ClusterNodeSimulator.
+func OwnerKey(nodesim *simv1.NodeSimulator) string {
+	if IsClusterScoped(nodesim) {
+		return nodesim.GetName()
+	}
+	return nodesim.GetNamespace() + "/" + nodesim.GetName()
+}
+
+// OwnerOf returns the NodeSimulator, or the ClusterNodeSimulator with an empty
+// namespace, recorded in the owner annotation of a node.
+func OwnerOf(node *v1.Node) (types.NamespacedName, bool) {
+	value, ok := node.GetAnnotations()[OwnerAnnotationKey]
+	if !ok {
+		return types.NamespacedName{}, false
+	}
+	parts := strings.SplitN(value, "/", 2)
+	if len(parts) != 2 {
+		return types.NamespacedName{Name: value}, value != ""
+	}
+	return types.NamespacedName{Namespace: parts[0], Name: parts[1]}, true
+}
+
+// IsForeign reports whether a node doesn't belong to nodesim: a real node, or a fake
+// node of another NodeSimulator.
+func IsForeign(node *v1.Node, nodesim *simv1.NodeSimulator) bool {
+	if node.GetLabels()[ManageLabelKey] != ManageLabelValue {
+		return true
+	}
+	if owner, ok := node.GetAnnotations()[OwnerAnnotationKey]; ok {
+		return owner != OwnerKey(nodesim)
+	}
+	id := node.GetLabels()[UniqueLabelKey]
+	return id != OwnerID(nodesim) && (id == "" || id != LegacyOwnerID(nodesim))
+}
+
+// ListFakeNodes lists the nodes of nodeSim. Nodes labeled by older releases are
+// adopted, relabeled and annotated with their owner, unless another NodeSimulator
+// may own them as well or they are annotated with another owner.
+func (r *SimReconciler) ListFakeNodes(ctx context.Context, nodeSim *simv1.NodeSimulator) ([]v1.Node, error) {
+	nodeList := &v1.NodeList{}
+	err := r.Client.List(ctx, nodeList, client.MatchingLabels{
+		ManageLabelKey: ManageLabelValue,
+		UniqueLabelKey: OwnerID(nodeSim),
+	})
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]v1.Node, 0, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		if IsForeign(&node, nodeSim) {
+			r.Recorder.Eventf(nodeSim, v1.EventTypeWarning, OwnershipConflictEventReason,
+				"Node %v is labeled for this NodeSimulator but owned by %v", node.GetName(), node.GetAnnotations()[OwnerAnnotationKey])
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+
+	if IsClusterScoped(nodeSim) {
+		return nodes, nil
+	}
+	legacyList := &v1.NodeList{}
+	err = r.Client.List(ctx, legacyList, client.MatchingLabels{
+		ManageLabelKey: ManageLabelValue,
+		UniqueLabelKey: LegacyOwnerID(nodeSim),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(legacyList.Items) == 0 {
+		return nodes, nil
+	}
+	shared, err := r.legacyIDShared(ctx, nodeSim)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range legacyList.Items {
+		_, annotated := node.GetAnnotations()[OwnerAnnotationKey]
+		if IsForeign(&node, nodeSim) || (!annotated && shared) {
+			r.Recorder.Eventf(nodeSim, v1.EventTypeWarning, OwnershipConflictEventReason,
+				"Node %v may belong to another NodeSimulator, it is left alone", node.GetName())
+			continue
+		}
+		if err := r.AdoptFakeNode(ctx, nodeSim, &node); err != nil {
+			klog.Errorf("NodeSim: %v/%v Adopt Node: %v Error: %v ", nodeSim.GetNamespace(), nodeSim.GetName(), node.GetName(), err)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// legacyIDShared reports whether another NodeSimulator has the legacy owner ID of nodeSim.
+func (r *SimReconciler) legacyIDShared(ctx context.Context, nodeSim *simv1.NodeSimulator) (bool, error) {
+	nodeSimList := &simv1.NodeSimulatorList{}
+	if err := r.Client.List(ctx, nodeSimList); err != nil {
+		return false, err
+	}
+	for _, other := range nodeSimList.Items {
+		if other.GetUID() != nodeSim.GetUID() && LegacyOwnerID(&other) == LegacyOwnerID(nodeSim) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// AdoptFakeNode labels the node with the owner ID of nodeSim and annotates it with
+// its owner.
+func (r *SimReconciler) AdoptFakeNode(ctx context.Context, nodeSim *simv1.NodeSimulator, node *v1.Node) error {
+	ops := []util.Ops{
+		{
+			Op:    "add",
+			Path:  "/metadata/labels/" + util.EscapeJSONPointer(UniqueLabelKey),
+			Value: OwnerID(nodeSim),
+		},
+	}
+	owner := map[string]string{
+		OwnerAnnotationKey:    OwnerKey(nodeSim),
+		OwnerUIDAnnotationKey: string(nodeSim.GetUID()),
+	}
+	if node.GetAnnotations() == nil {
+		ops = append(ops, util.Ops{
+			Op:    "add",
+			Path:  "/metadata/annotations",
+			Value: owner,
+		})
+	} else {
+		for key, value := range owner {
+			ops = append(ops, util.Ops{
+				Op:    "add",
+				Path:  "/metadata/annotations/" + util.EscapeJSONPointer(key),
+				Value: value,
+			})
+		}
+	}
+	return r.Client.Patch(ctx, node, &util.Patch{PatchOps: ops}, client.FieldOwner(FieldManager))
+}
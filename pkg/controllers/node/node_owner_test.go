@@ -0,0 +1,101 @@
+package node
+
+import (
+	"testing"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOwnerID(t *testing.T) {
+	newNodeSim := func(namespace, name string) *simv1.NodeSimulator {
+		return &simv1.NodeSimulator{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+
+	tests := []struct {
+		name  string
+		a, b  *simv1.NodeSimulator
+		equal bool
+	}{
+		{
+			name:  "same NodeSimulator",
+			a:     newNodeSim("default", "sim"),
+			b:     newNodeSim("default", "sim"),
+			equal: true,
+		},
+		{
+			name: "legacy ID collision",
+			a:    newNodeSim("a-b", "c"),
+			b:    newNodeSim("a", "b-c"),
+		},
+		{
+			name: "cluster-scoped and namespaced",
+			a:    newNodeSim("", "sim"),
+			b:    newNodeSim("default", "sim"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b := OwnerID(test.a), OwnerID(test.b)
+			if len(a) != 32 {
+				t.Errorf("expected a 32 character label value, got %q", a)
+			}
+			if (a == b) != test.equal {
+				t.Errorf("expected equal %v, got %q and %q", test.equal, a, b)
+			}
+		})
+	}
+}
+
+func TestIsForeign(t *testing.T) {
+	nodeSim := &simv1.NodeSimulator{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sim"}}
+	newNode := func(labels, annotations map[string]string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Labels: labels, Annotations: annotations}}
+	}
+
+	tests := []struct {
+		name     string
+		node     *v1.Node
+		expected bool
+	}{
+		{
+			name:     "real node",
+			node:     newNode(nil, nil),
+			expected: true,
+		},
+		{
+			name: "owner annotation",
+			node: newNode(map[string]string{ManageLabelKey: ManageLabelValue},
+				map[string]string{OwnerAnnotationKey: "default/sim"}),
+		},
+		{
+			name: "owner annotation of another NodeSimulator",
+			node: newNode(map[string]string{ManageLabelKey: ManageLabelValue, UniqueLabelKey: OwnerID(nodeSim)},
+				map[string]string{OwnerAnnotationKey: "default/other"}),
+			expected: true,
+		},
+		{
+			name: "owner ID label",
+			node: newNode(map[string]string{ManageLabelKey: ManageLabelValue, UniqueLabelKey: OwnerID(nodeSim)}, nil),
+		},
+		{
+			name: "legacy owner ID label",
+			node: newNode(map[string]string{ManageLabelKey: ManageLabelValue, UniqueLabelKey: "default-sim"}, nil),
+		},
+		{
+			name:     "fake node without owner",
+			node:     newNode(map[string]string{ManageLabelKey: ManageLabelValue}, nil),
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if foreign := IsForeign(test.node, nodeSim); foreign != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, foreign)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package node
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
+	cov1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsDraining reports whether the node has been picked for scale-down and is being drained.
+func IsDraining(node *v1.Node) bool {
+	_, ok := node.GetAnnotations()[DrainStartAnnotationKey]
+	return ok
+}
+
+// SelectScaleDownNodes returns count nodes of nodeList to remove, following the
+// scale-down policy of nodeSim. podCount maps node names to their managed pod number.
+func SelectScaleDownNodes(nodeSim *simv1.NodeSimulator, nodeList []v1.Node, podCount map[string]int, count int) []v1.Node {
+	if count <= 0 {
+		return nil
+	}
+	if count > len(nodeList) {
+		count = len(nodeList)
+	}
+
+	policy := simv1.ScaleDownHighestIndex
+	explicit := make(map[string]bool)
+	if nodeSim.Spec.ScaleDown != nil {
+		if nodeSim.Spec.ScaleDown.Policy != "" {
+			policy = nodeSim.Spec.ScaleDown.Policy
+		}
+		for _, name := range nodeSim.Spec.ScaleDown.NodeNames {
+			explicit[name] = true
+		}
+	}
+
+	candidates := make([]v1.Node, len(nodeList))
+	copy(candidates, nodeList)
+
+	// Nodes with the highest index go first whenever the policy ties.
+	higherIndex := func(i, j int) bool {
+		ii, _ := NodeIndex(nodeSim, candidates[i].GetName())
+		ij, _ := NodeIndex(nodeSim, candidates[j].GetName())
+		return ii > ij
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		switch policy {
+		case simv1.ScaleDownFewestPods:
+			pi, pj := podCount[candidates[i].GetName()], podCount[candidates[j].GetName()]
+			if pi != pj {
+				return pi < pj
+			}
+		case simv1.ScaleDownOldest:
+			ti, tj := candidates[i].GetCreationTimestamp(), candidates[j].GetCreationTimestamp()
+			if !ti.Equal(&tj) {
+				return ti.Before(&tj)
+			}
+		case simv1.ScaleDownExplicit:
+			ei, ej := explicit[candidates[i].GetName()], explicit[candidates[j].GetName()]
+			if ei != ej {
+				return ei
+			}
+		}
+		return higherIndex(i, j)
+	})
+
+	return candidates[:count]
+}
+
+// managedPodCount counts the managed pods bound to each node.
+func (r *SimReconciler) managedPodCount() (map[string]int, error) {
+	podList, err := r.ClientSet.CoreV1().Pods("").List(metav1.ListOptions{
+		LabelSelector: ManagedPodSelector.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	podCount := make(map[string]int)
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName != "" {
+			podCount[pod.Spec.NodeName]++
+		}
+	}
+	return podCount, nil
+}
+
+// StartDrain cordons the node and marks it as draining.
+func (r *SimReconciler) StartDrain(ctx context.Context, node *v1.Node) error {
+	ops := []util.Ops{
+		{
+			Op:    "add",
+			Path:  "/spec/unschedulable",
+			Value: true,
+		},
+	}
+	startTime := time.Now().Format(time.RFC3339)
+	if node.GetAnnotations() == nil {
+		ops = append(ops, util.Ops{
+			Op:    "add",
+			Path:  "/metadata/annotations",
+			Value: map[string]string{DrainStartAnnotationKey: startTime},
+		})
+	} else {
+		ops = append(ops, util.Ops{
+			Op:    "add",
+			Path:  "/metadata/annotations/" + util.EscapeJSONPointer(DrainStartAnnotationKey),
+			Value: startTime,
+		})
+	}
+	return r.Client.Patch(ctx, node, &util.Patch{PatchOps: ops}, client.FieldOwner(FieldManager))
+}
+
+// StopDrain unmarks a node marked by StartDrain, and uncordons it unless it was
+// unschedulable before.
+func (r *SimReconciler) StopDrain(ctx context.Context, node *v1.Node, unschedulable bool) error {
+	ops := []util.Ops{
+		{
+			Op:   "remove",
+			Path: "/metadata/annotations/" + util.EscapeJSONPointer(DrainStartAnnotationKey),
+		},
+	}
+	if !unschedulable {
+		ops = append(ops, util.Ops{
+			Op:   "remove",
+			Path: "/spec/unschedulable",
+		})
+	}
+	return r.Client.Patch(ctx, node, &util.Patch{PatchOps: ops}, client.FieldOwner(FieldManager))
+}
+
+// DrainNode evicts the managed pods of a draining node. It returns true once the
+// node is empty or the drain timeout has passed, so the node can be deleted.
+func (r *SimReconciler) DrainNode(nodeSim *simv1.NodeSimulator, node *v1.Node) bool {
+	spec := nodeSim.Spec.ScaleDown
+	if spec == nil {
+		return true
+	}
+
+	if spec.DrainTimeoutSeconds != nil {
+		startTime, err := time.Parse(time.RFC3339, node.GetAnnotations()[DrainStartAnnotationKey])
+		if err == nil && time.Since(startTime) > time.Duration(*spec.DrainTimeoutSeconds)*time.Second {
+			klog.Warningf("NodeSim: %v/%v Drain Node: %v Timeout", nodeSim.GetNamespace(), nodeSim.GetName(), node.GetName())
+			return true
+		}
+	}
+
+	podList, err := r.ClientSet.CoreV1().Pods("").List(metav1.ListOptions{
+		LabelSelector: ManagedPodSelector.String(),
+		FieldSelector: fields.Set{"spec.nodeName": node.GetName()}.AsSelector().String(),
+	})
+	if err != nil {
+		klog.Errorf("NodeSim: %v/%v Get Pod from node: %v Error: %v", nodeSim.GetNamespace(), nodeSim.GetName(), node.GetName(), err)
+		return false
+	}
+
+	for _, pod := range podList.Items {
+		if pod.GetDeletionTimestamp() != nil {
+			continue
+		}
+		eviction := &policyv1beta1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pod.GetName(),
+				Namespace: pod.GetNamespace(),
+			},
+			DeleteOptions: &metav1.DeleteOptions{
+				GracePeriodSeconds: spec.GracePeriodSeconds,
+			},
+		}
+		err := r.ClientSet.PolicyV1beta1().Evictions(pod.GetNamespace()).Evict(eviction)
+		if err != nil && !apierrors.IsNotFound(err) {
+			// TooManyRequests means a PodDisruptionBudget blocks the eviction, retry later.
+			klog.Warningf("NodeSim: %v/%v Evict Pod: %v/%v Error: %v", nodeSim.GetNamespace(), nodeSim.GetName(), pod.GetNamespace(), pod.GetName(), err)
+		} else if err == nil {
+			r.Recorder.Eventf(&pod, v1.EventTypeNormal, EvictedEventReason, "Evicted from node %v for scale-down", node.GetName())
+		}
+	}
+
+	return len(podList.Items) == 0
+}
+
+// DeleteFakeNode deletes the node, its lease and its CSINode.
+func (r *SimReconciler) DeleteFakeNode(ctx context.Context, nodeSim *simv1.NodeSimulator, nodeName string) {
+	node := &v1.Node{}
+	node.SetName(nodeName)
+	if err := r.Client.Delete(ctx, node); err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("NodeSim: %v/%v Delete Node: %v Error: %v", nodeSim.GetNamespace(), nodeSim.GetName(), nodeName, err)
+	}
+
+	nodeLease := &cov1.Lease{}
+	nodeLease.SetName(nodeName)
+	nodeLease.SetNamespace(NodeLeaseNamespace)
+	if err := r.Client.Delete(ctx, nodeLease); err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("NodeSim: %v/%v Delete Node Lease : %v Error: %v", nodeSim.GetNamespace(), nodeSim.GetName(), nodeName, err)
+	}
+
+	if len(nodeSim.Spec.CSIDrivers) > 0 {
+		if err := r.DeleteCSINode(ctx, nodeName); err != nil {
+			klog.Errorf("NodeSim: %v/%v Delete CSINode: %v Error: %v", nodeSim.GetNamespace(), nodeSim.GetName(), nodeName, err)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package node
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSelectScaleDownNodes(t *testing.T) {
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	newNode := func(name string, age time.Duration) v1.Node {
+		return v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(base.Add(-age)),
+		}}
+	}
+	nodeList := []v1.Node{
+		newNode("default-sim-0", 3*time.Hour),
+		newNode("default-sim-1", 1*time.Hour),
+		newNode("default-sim-2", 2*time.Hour),
+		newNode("default-sim-10", 4*time.Hour),
+	}
+	podCount := map[string]int{
+		"default-sim-0":  2,
+		"default-sim-1":  0,
+		"default-sim-2":  1,
+		"default-sim-10": 5,
+	}
+
+	tests := []struct {
+		name      string
+		scaleDown *simv1.ScaleDownSpec
+		count     int
+		expected  []string
+	}{
+		{
+			name:     "default policy removes the highest index",
+			count:    2,
+			expected: []string{"default-sim-10", "default-sim-2"},
+		},
+		{
+			name:      "fewest pods",
+			scaleDown: &simv1.ScaleDownSpec{Policy: simv1.ScaleDownFewestPods},
+			count:     2,
+			expected:  []string{"default-sim-1", "default-sim-2"},
+		},
+		{
+			name:      "oldest",
+			scaleDown: &simv1.ScaleDownSpec{Policy: simv1.ScaleDownOldest},
+			count:     2,
+			expected:  []string{"default-sim-10", "default-sim-0"},
+		},
+		{
+			name:      "explicit names first, then the highest index",
+			scaleDown: &simv1.ScaleDownSpec{Policy: simv1.ScaleDownExplicit, NodeNames: []string{"default-sim-0"}},
+			count:     2,
+			expected:  []string{"default-sim-0", "default-sim-10"},
+		},
+		{
+			name:     "count above the number of nodes",
+			count:    10,
+			expected: []string{"default-sim-10", "default-sim-2", "default-sim-1", "default-sim-0"},
+		},
+		{
+			name:  "zero count",
+			count: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodeSim := &simv1.NodeSimulator{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sim"},
+				Spec:       simv1.NodeSimulatorSpec{ScaleDown: test.scaleDown},
+			}
+			var names []string
+			for _, node := range SelectScaleDownNodes(nodeSim, nodeList, podCount, test.count) {
+				names = append(names, node.GetName())
+			}
+			if !reflect.DeepEqual(names, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package node
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	scvv1 "github.com/NJUPT-ISL/SCV/api/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// +kubebuilder:rbac:groups=core.run-linux.com,resources=scvs,verbs=get;list;watch;create;update;patch;delete
+
+const mebibyte = 1024 * 1024
+
+// GenSCVStatus returns the SCV inventory of the devices published to SCV. Each pod
+// bound to the node uses the scv/memory label MiB, or the whole memory, of the cards
+// it was allocated, or else of the scv/number cards with the most free memory.
+func GenSCVStatus(devices []Device, pods []v1.Pod) scvv1.ScvStatus {
+	cards := make(scvv1.CardList, 0)
+	cardIndex := make(map[string]int)
+	for _, device := range devices {
+		if device.Spec == nil || !device.Spec.SCV {
+			continue
+		}
+		health := "Healthy"
+		if !device.Healthy {
+			health = "Unhealthy"
+		}
+		memory := uint64(0)
+		if device.Memory != nil {
+			memory = uint64(device.Memory.Value()) / mebibyte
+		}
+		cardIndex[device.ID] = len(cards)
+		cards = append(cards, scvv1.Card{
+			ID:          uint(len(cards)),
+			Health:      health,
+			Model:       device.Model,
+			Power:       device.Spec.Power,
+			TotalMemory: memory,
+			Clock:       device.Spec.Clock,
+			FreeMemory:  memory,
+			Core:        device.Spec.Core,
+			Bandwidth:   device.Spec.Bandwidth,
+		})
+	}
+
+	use := func(card *scvv1.Card, memory uint64) {
+		if memory == 0 || memory > card.FreeMemory {
+			memory = card.FreeMemory
+		}
+		card.FreeMemory -= memory
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		memory, _ := strconv.ParseUint(pod.GetLabels()[SCVMemoryLabelKey], 10, 64)
+
+		allocated, _ := GetPodDevices(pod)
+		used := false
+		for _, resources := range allocated {
+			for _, ids := range resources {
+				for _, id := range ids {
+					if index, ok := cardIndex[id]; ok {
+						use(&cards[index], memory)
+						used = true
+					}
+				}
+			}
+		}
+		if used || memory == 0 {
+			continue
+		}
+
+		number, err := strconv.Atoi(pod.GetLabels()[SCVNumberLabelKey])
+		if err != nil || number <= 0 {
+			number = 1
+		}
+		order := make([]int, len(cards))
+		for j := range order {
+			order[j] = j
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return cards[order[a]].FreeMemory > cards[order[b]].FreeMemory
+		})
+		for j := 0; j < number && j < len(order); j++ {
+			use(&cards[order[j]], memory)
+		}
+	}
+
+	status := scvv1.ScvStatus{
+		CardList:   cards,
+		CardNumber: uint(len(cards)),
+	}
+	for _, card := range cards {
+		status.TotalMemorySum += card.TotalMemory
+		status.FreeMemorySum += card.FreeMemory
+	}
+	return status
+}
+
+// SyncSCV creates or updates the Scv object of a fake node whose devices are
+// published to SCV. The Scv object is owned by the node and removed with it.
+func (n *Updater) SyncSCV(ctx context.Context, node *v1.Node, pods []v1.Pod) error {
+	devices, err := NodeDevices(node)
+	if err != nil {
+		return err
+	}
+	published := false
+	for _, device := range devices {
+		published = published || (device.Spec != nil && device.Spec.SCV)
+	}
+	if !published {
+		return nil
+	}
+
+	status := GenSCVStatus(devices, pods)
+	status.UpdateTime = &metav1.Time{Time: time.Now()}
+
+	scv := &scvv1.Scv{}
+	err = n.Client.Get(ctx, types.NamespacedName{Name: node.GetName()}, scv)
+	if err != nil && apierrors.IsNotFound(err) {
+		scv = &scvv1.Scv{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: node.GetName(),
+				Labels: map[string]string{
+					ManageLabelKey: ManageLabelValue,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(node, v1.SchemeGroupVersion.WithKind("Node")),
+				},
+			},
+			Spec: scvv1.ScvSpec{
+				UpdateInterval: SCVUpdateInterval,
+			},
+			Status: status,
+		}
+		return n.Client.Create(ctx, scv)
+	} else if err != nil {
+		return err
+	}
+
+	previous := scv.Status.DeepCopy()
+	previous.UpdateTime = nil
+	current := status.DeepCopy()
+	current.UpdateTime = nil
+	if reflect.DeepEqual(previous, current) {
+		return nil
+	}
+	scv.Status = status
+	return n.Client.Update(ctx, scv)
+}
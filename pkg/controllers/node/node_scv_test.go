@@ -0,0 +1,114 @@
+package node
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGenSCVStatus(t *testing.T) {
+	const gpu = v1.ResourceName("nvidia.com/gpu")
+	memory := resource.MustParse("16Gi")
+	specs, _ := json.Marshal([]simv1.DeviceSpec{
+		{ResourceName: gpu, Count: 3, Model: "Tesla-V100", Memory: &memory, SCV: true},
+		{ResourceName: "xilinx.com/fpga", Count: 1},
+	})
+	devices, err := NodeDevices(&v1.Node{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		DevicesAnnotationKey:          string(specs),
+		UnhealthyDevicesAnnotationKey: "gpu-2",
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPod := func(labels map[string]string, phase v1.PodPhase, ids ...string) v1.Pod {
+		pod := v1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: labels}, Status: v1.PodStatus{Phase: phase}}
+		if len(ids) > 0 {
+			data, _ := json.Marshal(PodDevices{"c0": {gpu: ids}})
+			pod.Annotations = map[string]string{AllocatedDevicesAnnotationKey: string(data)}
+		}
+		return pod
+	}
+	mib := func(value string) map[string]string {
+		return map[string]string{SCVMemoryLabelKey: value}
+	}
+
+	tests := []struct {
+		name     string
+		pods     []v1.Pod
+		expected []uint64
+	}{
+		{
+			name:     "no pods",
+			expected: []uint64{16384, 16384, 16384},
+		},
+		{
+			name:     "label memory of the allocated cards",
+			pods:     []v1.Pod{newPod(mib("4096"), v1.PodRunning, "gpu-1")},
+			expected: []uint64{16384, 12288, 16384},
+		},
+		{
+			name:     "whole allocated cards without label memory",
+			pods:     []v1.Pod{newPod(nil, v1.PodRunning, "gpu-0", "gpu-2")},
+			expected: []uint64{0, 16384, 0},
+		},
+		{
+			name:     "label memory above the free memory",
+			pods:     []v1.Pod{newPod(mib("20000"), v1.PodRunning, "gpu-0")},
+			expected: []uint64{0, 16384, 16384},
+		},
+		{
+			name: "most free cards first without allocated cards",
+			pods: []v1.Pod{
+				newPod(mib("8192"), v1.PodRunning, "gpu-0"),
+				newPod(map[string]string{SCVMemoryLabelKey: "4096", SCVNumberLabelKey: "2"}, v1.PodRunning),
+			},
+			expected: []uint64{8192, 12288, 12288},
+		},
+		{
+			name:     "one card without the number label",
+			pods:     []v1.Pod{newPod(mib("4096"), v1.PodPending)},
+			expected: []uint64{12288, 16384, 16384},
+		},
+		{
+			name: "pods without cards or label memory, and finished pods, ignored",
+			pods: []v1.Pod{
+				newPod(nil, v1.PodRunning),
+				newPod(mib("4096"), v1.PodSucceeded, "gpu-0"),
+				newPod(mib("4096"), v1.PodFailed),
+			},
+			expected: []uint64{16384, 16384, 16384},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status := GenSCVStatus(devices, test.pods)
+			if status.CardNumber != 3 || len(status.CardList) != 3 {
+				t.Fatalf("expected 3 cards, got %d", status.CardNumber)
+			}
+			var free []uint64
+			var freeSum uint64
+			for i, card := range status.CardList {
+				free = append(free, card.FreeMemory)
+				freeSum += card.FreeMemory
+				if card.ID != uint(i) || card.TotalMemory != 16384 || card.Model != "Tesla-V100" {
+					t.Errorf("unexpected card %+v", card)
+				}
+			}
+			if !reflect.DeepEqual(free, test.expected) {
+				t.Errorf("expected free memory %v, got %v", test.expected, free)
+			}
+			if status.TotalMemorySum != 3*16384 || status.FreeMemorySum != freeSum {
+				t.Errorf("expected memory sums %d and %d, got %d and %d", 3*16384, freeSum, status.TotalMemorySum, status.FreeMemorySum)
+			}
+			if health := status.CardList[2].Health; health != "Unhealthy" {
+				t.Errorf("expected the unhealthy card, got %v", health)
+			}
+		})
+	}
+}
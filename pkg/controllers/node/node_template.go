@@ -1,11 +1,50 @@
 package node
 
 import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
 	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+	"strings"
 )
 
+// GenNodeName returns the name of the index-th fake node of nodesim.
+func GenNodeName(nodesim *simv1.NodeSimulator, index int) string {
+	return namePrefix(nodesim) + strconv.Itoa(index)
+}
+
+// ProviderID returns the providerID of a fake node, which identifies it to the
+// cluster-autoscaler.
+func ProviderID(nodeName string) string {
+	return ProviderIDPrefix + nodeName
+}
+
+// NodeIndex parses the index out of a fake node name generated by GenNodeName.
+func NodeIndex(nodesim *simv1.NodeSimulator, nodeName string) (int, bool) {
+	prefix := namePrefix(nodesim)
+	if !strings.HasPrefix(nodeName, prefix) {
+		return 0, false
+	}
+	index, err := strconv.Atoi(strings.TrimPrefix(nodeName, prefix))
+	if err != nil {
+		return 0, false
+	}
+	return index, true
+}
+
+// namePrefix prefixes the names of the fake nodes of nodesim, the nodes of a
+// ClusterNodeSimulator go without the namespace.
+func namePrefix(nodesim *simv1.NodeSimulator) string {
+	if IsClusterScoped(nodesim) {
+		return nodesim.GetName() + "-"
+	}
+	return nodesim.GetNamespace() + "-" + nodesim.GetName() + "-"
+}
+
 func GenNode(nodesim *simv1.NodeSimulator) (*v1.Node, error) {
 	labels := nodesim.GetLabels()
 
@@ -14,16 +53,27 @@ func GenNode(nodesim *simv1.NodeSimulator) (*v1.Node, error) {
 	}
 
 	labels[ManageLabelKey] = ManageLabelValue
-	labels[UniqueLabelKey] = nodesim.GetNamespace() + "-" + nodesim.GetName()
+	labels[UniqueLabelKey] = OwnerID(nodesim)
 
 	podCidr := ""
 	if len(nodesim.Spec.PodCIDRs) > 0 {
 		podCidr = nodesim.Spec.PodCIDRs[0]
 	}
 
+	ownedTaints, err := json.Marshal(nodesim.Spec.Taints)
+	if err != nil {
+		return nil, err
+	}
+
 	node := &v1.Node{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels: labels,
+			Annotations: map[string]string{
+				OwnedTaintsAnnotationKey:             string(ownedTaints),
+				ControllerManagedAttachAnnotationKey: "true",
+				OwnerAnnotationKey:                   OwnerKey(nodesim),
+				OwnerUIDAnnotationKey:                string(nodesim.GetUID()),
+			},
 		},
 		Spec: v1.NodeSpec{
 			PodCIDR:  podCidr,
@@ -45,5 +95,84 @@ func GenNode(nodesim *simv1.NodeSimulator) (*v1.Node, error) {
 			},
 		},
 	}
+	GenNodeInfo(nodesim, node)
+	if err := GenDeviceCapacity(nodesim, node); err != nil {
+		return nil, err
+	}
+	if err := GenCSIDriverCapacity(nodesim, node); err != nil {
+		return nil, err
+	}
 	return node, nil
 }
+
+// TemplateHash returns the hash of the node template, recorded in the
+// TemplateHashAnnotationKey annotation of the nodes generated from it.
+func TemplateHash(template *v1.Node) (string, error) {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data))[:16], nil
+}
+
+// MergeNodeSpec returns the spec of the live node with the fields owned by the
+// simulator taken from the template. Fields set by users or other controllers,
+// such as spec.unschedulable and foreign taints, are kept as they are.
+func MergeNodeSpec(live, template *v1.Node) v1.NodeSpec {
+	spec := *live.Spec.DeepCopy()
+
+	// PodCIDRs are immutable once set.
+	if spec.PodCIDR == "" {
+		spec.PodCIDR = template.Spec.PodCIDR
+		spec.PodCIDRs = template.Spec.PodCIDRs
+	}
+
+	// Drop the taints the simulator applied last time, keep the others and
+	// re-apply the taints from the template.
+	ownedTaints := make([]v1.Taint, 0)
+	if value, ok := live.GetAnnotations()[OwnedTaintsAnnotationKey]; ok {
+		_ = json.Unmarshal([]byte(value), &ownedTaints)
+	}
+	isOwned := func(taint v1.Taint, owned []v1.Taint) bool {
+		for i := range owned {
+			if owned[i].MatchTaint(&taint) {
+				return true
+			}
+		}
+		return false
+	}
+	taints := make([]v1.Taint, 0, len(spec.Taints)+len(template.Spec.Taints))
+	for _, taint := range spec.Taints {
+		if !isOwned(taint, ownedTaints) && !isOwned(taint, template.Spec.Taints) {
+			taints = append(taints, taint)
+		}
+	}
+	spec.Taints = append(taints, template.Spec.Taints...)
+	return spec
+}
+
+// GenNodeInfo applies the NodeInfo of the spec of nodesim over the default system
+// info of the node.
+func GenNodeInfo(nodesim *simv1.NodeSimulator, node *v1.Node) {
+	spec := nodesim.Spec.NodeInfo
+	if spec == nil {
+		return
+	}
+	info := &node.Status.NodeInfo
+	for _, field := range []struct {
+		value string
+		dst   *string
+	}{
+		{spec.OperatingSystem, &info.OperatingSystem},
+		{spec.Architecture, &info.Architecture},
+		{spec.OSImage, &info.OSImage},
+		{spec.KernelVersion, &info.KernelVersion},
+		{spec.KubeletVersion, &info.KubeletVersion},
+		{spec.KubeProxyVersion, &info.KubeProxyVersion},
+		{spec.ContainerRuntimeVersion, &info.ContainerRuntimeVersion},
+	} {
+		if field.value != "" {
+			*field.dst = field.value
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package node
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMergeNodeSpec(t *testing.T) {
+	ownedTaint := v1.Taint{Key: "sim", Value: "old", Effect: v1.TaintEffectNoSchedule}
+	foreignTaint := v1.Taint{Key: "user", Effect: v1.TaintEffectNoExecute}
+	templateTaint := v1.Taint{Key: "sim", Value: "new", Effect: v1.TaintEffectNoSchedule}
+	owned := func(taints ...v1.Taint) map[string]string {
+		data, _ := json.Marshal(taints)
+		return map[string]string{OwnedTaintsAnnotationKey: string(data)}
+	}
+
+	tests := []struct {
+		name     string
+		live     *v1.Node
+		template *v1.Node
+		expected v1.NodeSpec
+	}{
+		{
+			name: "scale-down cordon kept",
+			live: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{DrainStartAnnotationKey: "2020-01-01T00:00:00Z"}},
+				Spec:       v1.NodeSpec{Unschedulable: true},
+			},
+			template: &v1.Node{},
+			expected: v1.NodeSpec{Unschedulable: true, Taints: []v1.Taint{}},
+		},
+		{
+			name: "pod CIDRs set from the template when unset",
+			live: &v1.Node{},
+			template: &v1.Node{Spec: v1.NodeSpec{
+				PodCIDR:  "10.0.0.0/24",
+				PodCIDRs: []string{"10.0.0.0/24"},
+			}},
+			expected: v1.NodeSpec{
+				PodCIDR:  "10.0.0.0/24",
+				PodCIDRs: []string{"10.0.0.0/24"},
+				Taints:   []v1.Taint{},
+			},
+		},
+		{
+			name: "pod CIDRs kept once set",
+			live: &v1.Node{Spec: v1.NodeSpec{
+				PodCIDR:  "10.0.1.0/24",
+				PodCIDRs: []string{"10.0.1.0/24"},
+			}},
+			template: &v1.Node{Spec: v1.NodeSpec{
+				PodCIDR:  "10.0.0.0/24",
+				PodCIDRs: []string{"10.0.0.0/24"},
+			}},
+			expected: v1.NodeSpec{
+				PodCIDR:  "10.0.1.0/24",
+				PodCIDRs: []string{"10.0.1.0/24"},
+				Taints:   []v1.Taint{},
+			},
+		},
+		{
+			name: "owned taints replaced, foreign taints and unschedulable kept",
+			live: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Annotations: owned(ownedTaint)},
+				Spec: v1.NodeSpec{
+					Unschedulable: true,
+					Taints:        []v1.Taint{ownedTaint, foreignTaint},
+				},
+			},
+			template: &v1.Node{Spec: v1.NodeSpec{Taints: []v1.Taint{templateTaint}}},
+			expected: v1.NodeSpec{
+				Unschedulable: true,
+				Taints:        []v1.Taint{foreignTaint, templateTaint},
+			},
+		},
+		{
+			name: "taints removed from the template are dropped",
+			live: &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{Annotations: owned(ownedTaint)},
+				Spec:       v1.NodeSpec{Taints: []v1.Taint{ownedTaint}},
+			},
+			template: &v1.Node{},
+			expected: v1.NodeSpec{Taints: []v1.Taint{}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := MergeNodeSpec(test.live, test.template)
+			if !reflect.DeepEqual(spec, test.expected) {
+				t.Errorf("expected %+v, got %+v", test.expected, spec)
+			}
+		})
+	}
+}
@@ -3,12 +3,14 @@ package node
 import (
 	"context"
 	"errors"
-	"fmt"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/client-go/kubernetes"
 	"strconv"
 
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/metrics"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/shard"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/usage"
 	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -21,6 +23,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,17 +35,23 @@ import (
 type Updater struct {
 	Client    client.Client
 	ClientSet *kubernetes.Clientset
+	Recorder  record.EventRecorder
+	Usage     usage.Provider
 	Queue     workqueue.RateLimitingInterface
 	StopChan  chan struct{}
+	// Shard selects the nodes heartbeated by this replica, all of them when nil.
+	Shard *shard.Sharder
 }
 
-func NewNodeUpdater(updaterClient client.Client, clientSet *kubernetes.Clientset, queue workqueue.RateLimitingInterface, stopChan chan struct{}) (*Updater, error) {
-	if updaterClient == nil || queue == nil || stopChan == nil {
+func NewNodeUpdater(updaterClient client.Client, clientSet *kubernetes.Clientset, recorder record.EventRecorder, usageProvider usage.Provider, queue workqueue.RateLimitingInterface, stopChan chan struct{}) (*Updater, error) {
+	if updaterClient == nil || recorder == nil || queue == nil || stopChan == nil {
 		return nil, errors.New("New NodeUpdate Error, parameters contains nil ")
 	}
 	return &Updater{
 		Client:    updaterClient,
 		ClientSet: clientSet,
+		Recorder:  recorder,
+		Usage:     usageProvider,
 		Queue:     queue,
 		StopChan:  stopChan,
 	}, nil
@@ -90,7 +99,7 @@ func (n *Updater) InitUpdater() {
 			for _, node := range nodeList.Items {
 				getLabels := node.GetLabels()
 				if getLabels != nil {
-					if v, ok := getLabels[ManageLabelKey]; ok && v == ManageLabelValue {
+					if v, ok := getLabels[ManageLabelKey]; ok && v == ManageLabelValue && n.Shard.Owns(node.GetName()) {
 						n.Queue.Add(node.DeepCopy())
 					}
 				}
@@ -117,9 +126,19 @@ func (n *Updater) Run(threadiness int, stopCh chan struct{}) {
 }
 
 func (n *Updater) SyncNode(ctx context.Context, node *v1.Node) {
+	start := time.Now()
+	defer func() {
+		metrics.HeartbeatDuration.Observe(time.Since(start).Seconds())
+	}()
 
 	updateTime := metav1.Time{Time: time.Now()}
 
+	nodeName := node.GetName()
+	podList, podErr := n.ClientSet.CoreV1().Pods("").List(metav1.ListOptions{
+		LabelSelector: ManagedPodSelector.String(),
+		FieldSelector: fields.Set{"spec.nodeName": nodeName}.AsSelector().String(),
+	})
+
 	// Update Node Conditions
 	conditions := []v1.NodeCondition{
 		{
@@ -163,6 +182,12 @@ func (n *Updater) SyncNode(ctx context.Context, node *v1.Node) {
 			Type:               v1.NodeNetworkUnavailable,
 		},
 	}
+	if podErr == nil && n.Usage != nil && n.memoryPressure(ctx, node, podList.Items, updateTime.Time) {
+		conditions[2].Status = v1.ConditionTrue
+		conditions[2].Reason = InsufficientMemoryReason
+		conditions[2].Message = InsufficientMemoryMessage
+	}
+	n.recordConditionTransitions(node, conditions)
 	ops := []util.Ops{
 		{
 			Op:    "replace",
@@ -172,16 +197,14 @@ func (n *Updater) SyncNode(ctx context.Context, node *v1.Node) {
 	}
 	if err := n.Client.Status().Patch(ctx, node, &util.Patch{PatchOps: ops}); err != nil {
 		klog.Errorf("Sync Node: %v Error: %v", node.GetName(), err)
+		metrics.HeartbeatErrors.WithLabelValues(metrics.StepConditions).Inc()
+		n.Recorder.Eventf(node, v1.EventTypeWarning, FailedSyncEventReason, "Update node status error: %v", err)
 	}
 
 	// update allocate
-	nodeName := node.GetName()
-	podList, err := n.ClientSet.CoreV1().Pods("").List(metav1.ListOptions{
-		LabelSelector: fmt.Sprintf("%v=%v", ManageLabelKey, ManageLabelValue),
-		FieldSelector: fields.Set{"spec.nodeName": nodeName}.AsSelector().String(),
-	})
-	if err != nil {
-		klog.Errorf("Get Pod from node: %v Error: %v", nodeName, err)
+	if podErr != nil {
+		klog.Errorf("Get Pod from node: %v Error: %v", nodeName, podErr)
+		metrics.HeartbeatErrors.WithLabelValues(metrics.StepAllocatable).Inc()
 	} else {
 		resourceList := node.Status.Capacity.DeepCopy()
 		podCount := 0
@@ -201,6 +224,17 @@ func (n *Updater) SyncNode(ctx context.Context, node *v1.Node) {
 			}
 		}
 
+		// Unhealthy devices are not allocatable.
+		if devices, err := NodeDevices(node); err != nil {
+			klog.Errorf("Get Devices of node: %v Error: %v", nodeName, err)
+		} else {
+			for resourceName, value := range UnhealthyDeviceCount(devices) {
+				totalValue := resourceList[resourceName]
+				totalValue.Sub(value)
+				resourceList[resourceName] = totalValue.DeepCopy()
+			}
+		}
+
 		if resourceList.Pods() != nil {
 			podQua, err := resource.ParseQuantity(strconv.Itoa(podCount))
 			if err != nil {
@@ -218,8 +252,23 @@ func (n *Updater) SyncNode(ctx context.Context, node *v1.Node) {
 				Value: resourceList,
 			},
 		}
-		if err = n.Client.Status().Patch(ctx, node, &util.Patch{PatchOps: ops}); err != nil {
+		if err := n.Client.Status().Patch(ctx, node, &util.Patch{PatchOps: ops}); err != nil {
 			klog.Errorf("Sync Node: %v Error: %v", node.GetName(), err)
+			metrics.HeartbeatErrors.WithLabelValues(metrics.StepAllocatable).Inc()
+		}
+
+		// Only the nodes with CSI drivers have volumes
+		if _, ok := node.GetAnnotations()[CSIDriversAnnotationKey]; ok {
+			n.syncVolumeStatus(ctx, node, podList.Items)
+		}
+
+		if err := n.SyncSCV(ctx, node, podList.Items); err != nil {
+			klog.Errorf("Sync Node SCV: %v Error: %v", node.GetName(), err)
+			metrics.HeartbeatErrors.WithLabelValues(metrics.StepSCV).Inc()
+		}
+		if err := n.SyncResourceSlices(ctx, node); err != nil {
+			klog.Errorf("Sync Node ResourceSlices: %v Error: %v", node.GetName(), err)
+			metrics.HeartbeatErrors.WithLabelValues(metrics.StepDRA).Inc()
 		}
 	}
 
@@ -229,7 +278,7 @@ func (n *Updater) SyncNode(ctx context.Context, node *v1.Node) {
 	newLease := &cov1.Lease{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      node.GetName(),
-			Namespace: "kube-node-lease",
+			Namespace: NodeLeaseNamespace,
 		},
 		Spec: cov1.LeaseSpec{
 			HolderIdentity:       &nodeName,
@@ -237,17 +286,21 @@ func (n *Updater) SyncNode(ctx context.Context, node *v1.Node) {
 			RenewTime:            &renewTime,
 		},
 	}
-	err = n.Client.Get(ctx, types.NamespacedName{
+	err := n.Client.Get(ctx, types.NamespacedName{
 		Name:      node.GetName(),
-		Namespace: "kube-node-lease",
+		Namespace: NodeLeaseNamespace,
 	}, lease)
 	if err != nil && apierrors.IsNotFound(err) {
 		err := n.Client.Create(ctx, newLease)
 		if err != nil {
 			klog.Errorf("Sync Node Lease: %v Error: %v", node.GetName(), err)
+			metrics.HeartbeatErrors.WithLabelValues(metrics.StepLease).Inc()
 		}
 		return
 	}
+	if lease.Spec.RenewTime != nil {
+		metrics.LeaseRenewLag.Observe(renewTime.Sub(lease.Spec.RenewTime.Time).Seconds())
+	}
 
 	leaseOps := []util.Ops{
 		{
@@ -258,6 +311,56 @@ func (n *Updater) SyncNode(ctx context.Context, node *v1.Node) {
 	}
 	if err := n.Client.Patch(ctx, lease, &util.Patch{PatchOps: leaseOps}); err != nil {
 		klog.Errorf("Sync Node Lease: %v Error: %v", node.GetName(), err)
+		metrics.HeartbeatErrors.WithLabelValues(metrics.StepLease).Inc()
 	}
 
 }
+
+// recordConditionTransitions keeps the transition time of the conditions whose status
+// did not change, and records an event for the ones that did.
+func (n *Updater) recordConditionTransitions(node *v1.Node, conditions []v1.NodeCondition) {
+	for i := range conditions {
+		condition := &conditions[i]
+		var previous *v1.NodeCondition
+		for j := range node.Status.Conditions {
+			if node.Status.Conditions[j].Type == condition.Type {
+				previous = &node.Status.Conditions[j]
+				break
+			}
+		}
+		if previous != nil && previous.Status == condition.Status {
+			condition.LastTransitionTime = previous.LastTransitionTime
+			continue
+		}
+
+		reasons, ok := NodeConditionEventReasons[condition.Type]
+		if !ok {
+			continue
+		}
+		eventType, reason := v1.EventTypeNormal, reasons.Healthy
+		if condition.Status != reasons.HealthyStatus {
+			eventType, reason = v1.EventTypeWarning, reasons.Unhealthy
+		}
+		n.Recorder.Eventf(node, eventType, reason, "Node %v status is now: %v", node.GetName(), reason)
+	}
+}
+
+// memoryPressure reports whether the simulated usage of the running pods leaves less
+// than MemoryEvictionThreshold of the node memory available, like the kubelet
+// eviction manager would.
+func (n *Updater) memoryPressure(ctx context.Context, node *v1.Node, pods []v1.Pod, now time.Time) bool {
+	capacity, ok := node.Status.Capacity[v1.ResourceMemory]
+	if !ok {
+		return false
+	}
+	running := make([]v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodRunning {
+			running = append(running, pod)
+		}
+	}
+	used := usage.NodeUsage(ctx, n.Usage, running, now)[v1.ResourceMemory]
+	available := capacity.DeepCopy()
+	available.Sub(used)
+	return available.Cmp(resource.MustParse(MemoryEvictionThreshold)) < 0
+}
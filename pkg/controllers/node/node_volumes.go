@@ -0,0 +1,230 @@
+package node
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/metrics"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims;persistentvolumes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=csidrivers,verbs=get;list;watch
+
+// CSIDriverGVK is read as an unstructured object, the client-go in use only has
+// the storage.k8s.io/v1beta1 CSIDriver.
+var CSIDriverGVK = schema.GroupVersionKind{Group: "storage.k8s.io", Version: "v1", Kind: "CSIDriver"}
+
+// GenCSIDriverCapacity sets the attach limits of the CSI drivers of nodesim on the node.
+func GenCSIDriverCapacity(nodesim *simv1.NodeSimulator, node *v1.Node) error {
+	if len(nodesim.Spec.CSIDrivers) == 0 {
+		return nil
+	}
+	drivers, err := json.Marshal(nodesim.Spec.CSIDrivers)
+	if err != nil {
+		return err
+	}
+	node.Annotations[CSIDriversAnnotationKey] = string(drivers)
+
+	capacity := node.Status.Capacity.DeepCopy()
+	if capacity == nil {
+		capacity = v1.ResourceList{}
+	}
+	for _, driver := range nodesim.Spec.CSIDrivers {
+		if driver.MaxVolumes == nil {
+			continue
+		}
+		capacity[AttachLimitResourceName(driver.Name)] = *resource.NewQuantity(*driver.MaxVolumes, resource.DecimalSI)
+	}
+	node.Status.Capacity = capacity
+	node.Status.Allocatable = capacity
+	return nil
+}
+
+// AttachLimitResourceName returns the resource the attach limit of a CSI driver is reported as.
+func AttachLimitResourceName(driver string) v1.ResourceName {
+	return v1.ResourceName(AttachLimitResourcePrefix + driver)
+}
+
+// NodeCSIDrivers returns the CSI drivers installed on a fake node.
+func NodeCSIDrivers(node *v1.Node) ([]simv1.CSIDriverSpec, error) {
+	value, ok := node.GetAnnotations()[CSIDriversAnnotationKey]
+	if !ok {
+		return nil, nil
+	}
+	drivers := make([]simv1.CSIDriverSpec, 0)
+	if err := json.Unmarshal([]byte(value), &drivers); err != nil {
+		return nil, err
+	}
+	return drivers, nil
+}
+
+// CSIVolumeName returns the unique name of a CSI volume, as reported in the node status.
+func CSIVolumeName(driver, volumeHandle string) v1.UniqueVolumeName {
+	return v1.UniqueVolumeName(CSIVolumeNamePrefix + driver + "^" + volumeHandle)
+}
+
+// VolumeAttachmentName returns the name the attach-detach controller gives the
+// VolumeAttachment of a CSI volume on a node.
+func VolumeAttachmentName(volumeHandle, driver, nodeName string) string {
+	return fmt.Sprintf("csi-%x", sha256.Sum256([]byte(volumeHandle+driver+nodeName)))
+}
+
+// PodCSIVolumes returns the CSI volumes of the PersistentVolumeClaims of a pod. It
+// returns false while a claim is not bound yet.
+func PodCSIVolumes(ctx context.Context, c client.Client, pod *v1.Pod) ([]*v1.CSIPersistentVolumeSource, bool, error) {
+	volumes := make([]*v1.CSIPersistentVolumeSource, 0)
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc := &v1.PersistentVolumeClaim{}
+		err := c.Get(ctx, types.NamespacedName{Namespace: pod.GetNamespace(), Name: volume.PersistentVolumeClaim.ClaimName}, pvc)
+		if err != nil && apierrors.IsNotFound(err) {
+			return nil, false, nil
+		} else if err != nil {
+			return nil, false, err
+		}
+		if pvc.Status.Phase != v1.ClaimBound || pvc.Spec.VolumeName == "" {
+			return nil, false, nil
+		}
+
+		pv := &v1.PersistentVolume{}
+		if err := c.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, pv); err != nil {
+			return nil, false, err
+		}
+		if pv.Spec.CSI != nil {
+			volumes = append(volumes, pv.Spec.CSI)
+		}
+	}
+	return volumes, true, nil
+}
+
+// AttachRequired reports whether the volumes of a CSI driver are attached before they
+// are mounted, which is the default when the driver has no CSIDriver object.
+func AttachRequired(ctx context.Context, c client.Client, driver string) (bool, error) {
+	csiDriver := &unstructured.Unstructured{}
+	csiDriver.SetGroupVersionKind(CSIDriverGVK)
+	err := c.Get(ctx, types.NamespacedName{Name: driver}, csiDriver)
+	if err != nil && apierrors.IsNotFound(err) {
+		return true, nil
+	} else if err != nil {
+		return false, err
+	}
+	required, found, _ := unstructured.NestedBool(csiDriver.Object, "spec", "attachRequired")
+	return required || !found, nil
+}
+
+// GenVolumeStatus returns the volumes in use by the running pods of a node, and the
+// volumes attached to it by VolumeAttachments.
+func (n *Updater) GenVolumeStatus(ctx context.Context, node *v1.Node, pods []v1.Pod) ([]v1.UniqueVolumeName, []v1.AttachedVolume, error) {
+	inUse := make([]v1.UniqueVolumeName, 0)
+	seen := make(map[v1.UniqueVolumeName]bool)
+	for i := range pods {
+		if pods[i].Status.Phase != v1.PodRunning {
+			continue
+		}
+		volumes, _, err := PodCSIVolumes(ctx, n.Client, &pods[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, volume := range volumes {
+			name := CSIVolumeName(volume.Driver, volume.VolumeHandle)
+			if !seen[name] {
+				seen[name] = true
+				inUse = append(inUse, name)
+			}
+		}
+	}
+	sort.Slice(inUse, func(i, j int) bool { return inUse[i] < inUse[j] })
+
+	attachments := &storagev1.VolumeAttachmentList{}
+	if err := n.Client.List(ctx, attachments); err != nil {
+		return nil, nil, err
+	}
+	attached := make([]v1.AttachedVolume, 0)
+	for _, attachment := range attachments.Items {
+		if attachment.Spec.NodeName != node.GetName() || !attachment.Status.Attached ||
+			attachment.Spec.Source.PersistentVolumeName == nil {
+			continue
+		}
+		pv := &v1.PersistentVolume{}
+		if err := n.Client.Get(ctx, types.NamespacedName{Name: *attachment.Spec.Source.PersistentVolumeName}, pv); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+		if pv.Spec.CSI == nil {
+			continue
+		}
+		attached = append(attached, v1.AttachedVolume{
+			Name:       CSIVolumeName(pv.Spec.CSI.Driver, pv.Spec.CSI.VolumeHandle),
+			DevicePath: attachment.Status.AttachmentMetadata["devicePath"],
+		})
+	}
+	sort.Slice(attached, func(i, j int) bool { return attached[i].Name < attached[j].Name })
+	return inUse, attached, nil
+}
+
+// syncVolumeStatus reports the CSI volumes in use by the pods of the node and the ones
+// attached to it in its status, when they changed.
+func (n *Updater) syncVolumeStatus(ctx context.Context, node *v1.Node, pods []v1.Pod) {
+	inUse, attached, err := n.GenVolumeStatus(ctx, node, pods)
+	if err != nil {
+		klog.Errorf("Get Volumes of node: %v Error: %v", node.GetName(), err)
+		metrics.HeartbeatErrors.WithLabelValues(metrics.StepVolumes).Inc()
+		return
+	}
+	if !VolumeStatusChanged(node, inUse, attached) {
+		return
+	}
+	ops := []util.Ops{
+		{
+			Op:    "add",
+			Path:  "/status/volumesInUse",
+			Value: inUse,
+		},
+		{
+			Op:    "add",
+			Path:  "/status/volumesAttached",
+			Value: attached,
+		},
+	}
+	if err := n.Client.Status().Patch(ctx, node, &util.Patch{PatchOps: ops}); err != nil {
+		klog.Errorf("Sync Node Volumes: %v Error: %v", node.GetName(), err)
+		metrics.HeartbeatErrors.WithLabelValues(metrics.StepVolumes).Inc()
+	}
+}
+
+// VolumeStatusChanged reports whether the volumes in the status of the node differ
+// from inUse and attached.
+func VolumeStatusChanged(node *v1.Node, inUse []v1.UniqueVolumeName, attached []v1.AttachedVolume) bool {
+	if len(node.Status.VolumesInUse) != len(inUse) || len(node.Status.VolumesAttached) != len(attached) {
+		return true
+	}
+	for i := range inUse {
+		if node.Status.VolumesInUse[i] != inUse[i] {
+			return true
+		}
+	}
+	for i := range attached {
+		if node.Status.VolumesAttached[i] != attached[i] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package node
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPVC(name, volumeName string, phase v1.PersistentVolumeClaimPhase) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec:       v1.PersistentVolumeClaimSpec{VolumeName: volumeName},
+		Status:     v1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
+
+func newPV(name string, csi *v1.CSIPersistentVolumeSource) *v1.PersistentVolume {
+	pv := &v1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if csi != nil {
+		pv.Spec.CSI = csi
+	} else {
+		pv.Spec.HostPath = &v1.HostPathVolumeSource{Path: "/data"}
+	}
+	return pv
+}
+
+func newVolumePod(name, nodeName string, phase v1.PodPhase, claims ...string) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec:       v1.PodSpec{NodeName: nodeName},
+		Status:     v1.PodStatus{Phase: phase},
+	}
+	for _, claim := range claims {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name:         claim,
+			VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+		})
+	}
+	return pod
+}
+
+func newCSIDriver(name string, attachRequired *bool) *unstructured.Unstructured {
+	csiDriver := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{}}}
+	csiDriver.SetGroupVersionKind(CSIDriverGVK)
+	csiDriver.SetName(name)
+	if attachRequired != nil {
+		_ = unstructured.SetNestedField(csiDriver.Object, *attachRequired, "spec", "attachRequired")
+	}
+	return csiDriver
+}
+
+// newVolumeClient returns a fake client holding objects, the CSIDrivers are created
+// separately as the scheme has no typed storage.k8s.io/v1 CSIDriver.
+func newVolumeClient(t *testing.T, objects []runtime.Object, csiDrivers ...*unstructured.Unstructured) client.Client {
+	c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, objects...)
+	for _, csiDriver := range csiDrivers {
+		if err := c.Create(context.TODO(), csiDriver); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return c
+}
+
+func TestPodCSIVolumes(t *testing.T) {
+	ebs := &v1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-1"}
+	c := newVolumeClient(t, []runtime.Object{
+		newPVC("bound", "pv-ebs", v1.ClaimBound),
+		newPVC("host", "pv-host", v1.ClaimBound),
+		newPVC("pending", "", v1.ClaimPending),
+		newPVC("unset", "pv-ebs", v1.ClaimPending),
+		newPV("pv-ebs", ebs),
+		newPV("pv-host", nil),
+	})
+
+	tests := []struct {
+		name     string
+		claims   []string
+		volumes  []*v1.CSIPersistentVolumeSource
+		expected bool
+	}{
+		{name: "no claims", volumes: []*v1.CSIPersistentVolumeSource{}, expected: true},
+		{name: "bound CSI claim", claims: []string{"bound"}, volumes: []*v1.CSIPersistentVolumeSource{ebs}, expected: true},
+		{name: "bound claim of another volume type", claims: []string{"host"}, volumes: []*v1.CSIPersistentVolumeSource{}, expected: true},
+		{name: "pending claim", claims: []string{"bound", "pending"}},
+		{name: "claim with a volume name not bound yet", claims: []string{"unset"}},
+		{name: "missing claim", claims: []string{"missing"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			volumes, bound, err := PodCSIVolumes(context.TODO(), c, newVolumePod("pod", "fake-0", v1.PodPending, test.claims...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bound != test.expected {
+				t.Errorf("expected bound %v, got %v", test.expected, bound)
+			}
+			if !reflect.DeepEqual(volumes, test.volumes) {
+				t.Errorf("expected volumes %v, got %v", test.volumes, volumes)
+			}
+		})
+	}
+}
+
+func TestAttachRequired(t *testing.T) {
+	required, notRequired := true, false
+	c := newVolumeClient(t, nil,
+		newCSIDriver("required.csi.example.com", &required),
+		newCSIDriver("not-required.csi.example.com", &notRequired),
+		newCSIDriver("unset.csi.example.com", nil),
+	)
+
+	tests := []struct {
+		name     string
+		driver   string
+		expected bool
+	}{
+		{name: "attach required", driver: "required.csi.example.com", expected: true},
+		{name: "attach not required", driver: "not-required.csi.example.com"},
+		{name: "attachRequired unset", driver: "unset.csi.example.com", expected: true},
+		{name: "no CSIDriver object", driver: "missing.csi.example.com", expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := AttachRequired(context.TODO(), c, test.driver)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestGenVolumeStatus(t *testing.T) {
+	ebs1 := &v1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-1"}
+	ebs2 := &v1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-2"}
+	newAttachment := func(name, nodeName, pvName string, attached bool) *storagev1.VolumeAttachment {
+		return &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: "ebs.csi.aws.com",
+				NodeName: nodeName,
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+			},
+			Status: storagev1.VolumeAttachmentStatus{
+				Attached:           attached,
+				AttachmentMetadata: map[string]string{"devicePath": "/dev/" + name},
+			},
+		}
+	}
+	n := &Updater{Client: newVolumeClient(t, []runtime.Object{
+		newPVC("data-1", "pv-1", v1.ClaimBound),
+		newPVC("data-2", "pv-2", v1.ClaimBound),
+		newPV("pv-1", ebs1),
+		newPV("pv-2", ebs2),
+		newAttachment("attached", "fake-0", "pv-1", true),
+		newAttachment("attaching", "fake-0", "pv-2", false),
+		newAttachment("other-node", "fake-1", "pv-2", true),
+		newAttachment("missing-pv", "fake-0", "pv-9", true),
+	})}
+	pods := []v1.Pod{
+		*newVolumePod("a", "fake-0", v1.PodRunning, "data-2", "data-1"),
+		*newVolumePod("b", "fake-0", v1.PodRunning, "data-1"),
+		*newVolumePod("c", "fake-0", v1.PodPending, "missing"),
+	}
+
+	inUse, attached, err := n.GenVolumeStatus(context.TODO(), &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "fake-0"}}, pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedInUse := []v1.UniqueVolumeName{CSIVolumeName(ebs1.Driver, ebs1.VolumeHandle), CSIVolumeName(ebs2.Driver, ebs2.VolumeHandle)}
+	if !reflect.DeepEqual(inUse, expectedInUse) {
+		t.Errorf("expected volumes in use %v, got %v", expectedInUse, inUse)
+	}
+	expectedAttached := []v1.AttachedVolume{{Name: expectedInUse[0], DevicePath: "/dev/attached"}}
+	if !reflect.DeepEqual(attached, expectedAttached) {
+		t.Errorf("expected attached volumes %v, got %v", expectedAttached, attached)
+	}
+	if VolumeStatusChanged(&v1.Node{Status: v1.NodeStatus{VolumesInUse: inUse, VolumesAttached: attached}}, inUse, attached) {
+		t.Errorf("expected the same volumes to be unchanged")
+	}
+	if !VolumeStatusChanged(&v1.Node{}, inUse, attached) {
+		t.Errorf("expected new volumes to be changed")
+	}
+}
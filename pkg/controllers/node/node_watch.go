@@ -0,0 +1,128 @@
+package node
+
+import (
+	"context"
+	"time"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	cov1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// driftHandler enqueues the NodeSimulator of a fake node when the node or its lease
+// is deleted, or when the node is changed outside of its heartbeat.
+type driftHandler struct {
+	r *SimReconciler
+	// cluster enqueues the ClusterNodeSimulators instead of the NodeSimulators.
+	cluster bool
+}
+
+// Create implements handler.EventHandler
+func (h *driftHandler) Create(event.CreateEvent, workqueue.RateLimitingInterface) {}
+
+// Update implements handler.EventHandler
+func (h *driftHandler) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	oldNode, ok := evt.ObjectOld.(*v1.Node)
+	if !ok {
+		return
+	}
+	newNode, ok := evt.ObjectNew.(*v1.Node)
+	if !ok || !nodeDrifted(oldNode, newNode) {
+		return
+	}
+	h.enqueue(newNode, q)
+}
+
+// Delete implements handler.EventHandler
+func (h *driftHandler) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	switch obj := evt.Object.(type) {
+	case *v1.Node:
+		h.enqueue(obj, q)
+	case *cov1.Lease:
+		if obj.GetNamespace() != NodeLeaseNamespace {
+			return
+		}
+		node := &v1.Node{}
+		if err := h.r.Client.Get(context.TODO(), types.NamespacedName{Name: obj.GetName()}, node); err != nil {
+			return
+		}
+		h.enqueue(node, q)
+	}
+}
+
+// Generic implements handler.EventHandler
+func (h *driftHandler) Generic(event.GenericEvent, workqueue.RateLimitingInterface) {}
+
+func (h *driftHandler) enqueue(node *v1.Node, q workqueue.RateLimitingInterface) {
+	if node.GetLabels()[ManageLabelKey] != ManageLabelValue {
+		return
+	}
+	if owner, ok := OwnerOf(node); ok {
+		if (owner.Namespace == "") == h.cluster {
+			q.Add(reconcile.Request{NamespacedName: owner})
+		}
+		return
+	}
+
+	// Nodes of older releases carry no owner annotation
+	value, ok := node.GetLabels()[UniqueLabelKey]
+	if !ok || h.cluster {
+		return
+	}
+	nodeSimList := &simv1.NodeSimulatorList{}
+	if err := h.r.Client.List(context.TODO(), nodeSimList); err != nil {
+		klog.Errorf("Node: %v List NodeSim Error: %v", node.GetName(), err)
+		return
+	}
+	for _, nodeSim := range nodeSimList.Items {
+		if OwnerID(&nodeSim) == value || LegacyOwnerID(&nodeSim) == value {
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: nodeSim.GetNamespace(), Name: nodeSim.GetName()}})
+		}
+	}
+}
+
+// nodeDrifted reports whether a node update changed more than the heartbeat of the
+// simulator does, that is its conditions, allocatable, volumes and resource version.
+func nodeDrifted(oldNode, newNode *v1.Node) bool {
+	return !equality.Semantic.DeepEqual(oldNode.Spec, newNode.Spec) ||
+		!equality.Semantic.DeepEqual(oldNode.GetLabels(), newNode.GetLabels()) ||
+		!equality.Semantic.DeepEqual(oldNode.GetAnnotations(), newNode.GetAnnotations()) ||
+		!equality.Semantic.DeepEqual(oldNode.Status.Capacity, newNode.Status.Capacity) ||
+		!equality.Semantic.DeepEqual(oldNode.Status.Addresses, newNode.Status.Addresses) ||
+		oldNode.Status.DaemonEndpoints != newNode.Status.DaemonEndpoints
+}
+
+// HealLease recreates the lease of a fake node deleted by others, the heartbeat
+// renews it afterwards.
+func (r *SimReconciler) HealLease(ctx context.Context, nodeSim *simv1.NodeSimulator, node *v1.Node) {
+	lease := &cov1.Lease{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: NodeLeaseNamespace, Name: node.GetName()}, lease)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return
+	}
+	nodeName := node.GetName()
+	leasePeriod := int32(40)
+	renewTime := metav1.MicroTime{Time: time.Now()}
+	lease = &cov1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeName,
+			Namespace: NodeLeaseNamespace,
+		},
+		Spec: cov1.LeaseSpec{
+			HolderIdentity:       &nodeName,
+			LeaseDurationSeconds: &leasePeriod,
+			RenewTime:            &renewTime,
+		},
+	}
+	if err := r.Client.Create(ctx, lease); err != nil && !apierrors.IsAlreadyExists(err) {
+		klog.Errorf("NodeSim: %v/%v Create Node Lease: %v Error: %v", nodeSim.GetNamespace(), nodeSim.GetName(), nodeName, err)
+	}
+}
@@ -0,0 +1,165 @@
+package node
+
+import (
+	"reflect"
+	"testing"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	cov1 "k8s.io/api/coordination/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestNodeDrifted(t *testing.T) {
+	base := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "fake-0",
+			ResourceVersion: "1",
+			Labels:          map[string]string{ManageLabelKey: ManageLabelValue},
+			Annotations:     map[string]string{OwnerAnnotationKey: "default/sim"},
+		},
+		Status: v1.NodeStatus{
+			Capacity:    v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+			Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4")},
+			Conditions:  []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		update   func(node *v1.Node)
+		expected bool
+	}{
+		{
+			name: "heartbeat",
+			update: func(node *v1.Node) {
+				node.ResourceVersion = "2"
+				node.Status.Conditions[0].LastHeartbeatTime = metav1.Now()
+				node.Status.Allocatable[v1.ResourceCPU] = resource.MustParse("3")
+				node.Status.VolumesInUse = []v1.UniqueVolumeName{"volume"}
+			},
+		},
+		{
+			name:     "spec",
+			update:   func(node *v1.Node) { node.Spec.Unschedulable = true },
+			expected: true,
+		},
+		{
+			name:     "labels",
+			update:   func(node *v1.Node) { node.Labels["team"] = "ml" },
+			expected: true,
+		},
+		{
+			name:     "annotations",
+			update:   func(node *v1.Node) { delete(node.Annotations, OwnerAnnotationKey) },
+			expected: true,
+		},
+		{
+			name:     "capacity",
+			update:   func(node *v1.Node) { node.Status.Capacity[v1.ResourceCPU] = resource.MustParse("8") },
+			expected: true,
+		},
+		{
+			name: "addresses",
+			update: func(node *v1.Node) {
+				node.Status.Addresses = []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: "10.0.0.1"}}
+			},
+			expected: true,
+		},
+		{
+			name:     "daemon endpoints",
+			update:   func(node *v1.Node) { node.Status.DaemonEndpoints.KubeletEndpoint.Port = 10250 },
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			newNode := base.DeepCopy()
+			test.update(newNode)
+			if drifted := nodeDrifted(base, newNode); drifted != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, drifted)
+			}
+		})
+	}
+}
+
+func TestDriftHandlerEnqueue(t *testing.T) {
+	nodeSim := &simv1.NodeSimulator{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sim"}}
+	newNode := func(name string, labels, annotations map[string]string) *v1.Node {
+		all := map[string]string{ManageLabelKey: ManageLabelValue}
+		for key, value := range labels {
+			all[key] = value
+		}
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: all, Annotations: annotations}}
+	}
+	namespaced := newNode("default-sim-0", nil, map[string]string{OwnerAnnotationKey: "default/sim"})
+	cluster := newNode("pool-0", nil, map[string]string{OwnerAnnotationKey: "pool"})
+	legacy := newNode("default-sim-1", map[string]string{UniqueLabelKey: LegacyOwnerID(nodeSim)}, nil)
+	hashed := newNode("default-sim-2", map[string]string{UniqueLabelKey: OwnerID(nodeSim)}, nil)
+	unknown := newNode("other-0", map[string]string{UniqueLabelKey: "other"}, nil)
+	realNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "real", Annotations: map[string]string{OwnerAnnotationKey: "default/sim"}}}
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := simv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	r := &SimReconciler{Client: fake.NewFakeClientWithScheme(scheme, nodeSim, namespaced, cluster)}
+	lease := func(namespace, name string) *cov1.Lease {
+		return &cov1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	}
+	simRequest := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "sim"}}
+	poolRequest := reconcile.Request{NamespacedName: types.NamespacedName{Name: "pool"}}
+
+	tests := []struct {
+		name     string
+		cluster  bool
+		object   runtime.Object
+		expected []reconcile.Request
+	}{
+		{name: "NodeSimulator node", object: namespaced, expected: []reconcile.Request{simRequest}},
+		{name: "NodeSimulator node to the cluster handler", cluster: true, object: namespaced},
+		{name: "ClusterNodeSimulator node", cluster: true, object: cluster, expected: []reconcile.Request{poolRequest}},
+		{name: "ClusterNodeSimulator node to the namespaced handler", object: cluster},
+		{name: "legacy label", object: legacy, expected: []reconcile.Request{simRequest}},
+		{name: "hashed label without owner annotation", object: hashed, expected: []reconcile.Request{simRequest}},
+		{name: "legacy label to the cluster handler", cluster: true, object: legacy},
+		{name: "label of no NodeSimulator", object: unknown},
+		{name: "real node", object: realNode},
+		{name: "node lease", object: lease(NodeLeaseNamespace, "default-sim-0"), expected: []reconcile.Request{simRequest}},
+		{name: "cluster node lease", cluster: true, object: lease(NodeLeaseNamespace, "pool-0"), expected: []reconcile.Request{poolRequest}},
+		{name: "lease of another namespace", object: lease("default", "default-sim-0")},
+		{name: "lease of a missing node", object: lease(NodeLeaseNamespace, "default-sim-9")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h := &driftHandler{r: r, cluster: test.cluster}
+			q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+			defer q.ShutDown()
+			meta, _ := test.object.(metav1.Object)
+			h.Delete(event.DeleteEvent{Meta: meta, Object: test.object}, q)
+
+			var requests []reconcile.Request
+			for q.Len() > 0 {
+				item, _ := q.Get()
+				requests = append(requests, item.(reconcile.Request))
+				q.Done(item)
+			}
+			if !reflect.DeepEqual(requests, test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, requests)
+			}
+		})
+	}
+}
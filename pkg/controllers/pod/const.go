@@ -0,0 +1,37 @@
+package pod
+
+import "time"
+
+const (
+	// ShutdownAnnotationKey overrides the simulated shutdown time of a pod, in seconds.
+	ShutdownAnnotationKey = "sim.k8s.io/shutdown-seconds"
+	// RunAnnotationKey is the time in seconds the containers of a pod run before they
+	// complete and the pod succeeds, fractions allowed.
+	RunAnnotationKey = "sim.k8s.io/run-seconds"
+
+	// Reason
+	TerminatedReason = "Completed"
+	OOMKilledReason  = "OOMKilled"
+	// UnexpectedAdmissionErrorReason is set on pods the node has no devices for.
+	UnexpectedAdmissionErrorReason = "UnexpectedAdmissionError"
+	// PreparedReason is set on the Ready condition of the claimed DRA devices.
+	PreparedReason = "Prepared"
+
+	// OOMExitCode is the exit code of a container killed by SIGKILL.
+	OOMExitCode = 137
+	// OOMCheckPeriod is how often the memory usage of running pods is checked against their limits.
+	OOMCheckPeriod = 30 * time.Second
+	// ClaimRequeuePeriod is how often a pod waiting for its ResourceClaims is checked.
+	ClaimRequeuePeriod = 5 * time.Second
+	// VolumeRequeuePeriod is how often a pod waiting for its volumes is checked.
+	VolumeRequeuePeriod = 5 * time.Second
+
+	// Event Reason
+	PullingEventReason    = "Pulling"
+	PulledEventReason     = "Pulled"
+	CreatedEventReason    = "Created"
+	StartedEventReason    = "Started"
+	KillingEventReason    = "Killing"
+	OOMKillingEventReason = "OOMKilling"
+	FailedSyncEventReason = "FailedSync"
+)
@@ -0,0 +1,56 @@
+package pod
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+)
+
+// runPeriod returns how long the containers of the pod run before they complete,
+// read from the RunAnnotationKey annotation. Pods without it run until deleted.
+func runPeriod(pod *v1.Pod) (time.Duration, bool) {
+	value, ok := pod.GetAnnotations()[RunAnnotationKey]
+	if !ok {
+		return 0, false
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || seconds < 0 {
+		klog.Errorf("Pod: %v/%v Parse Annotation %v: %q Error: %v", pod.GetNamespace(), pod.GetName(), RunAnnotationKey, value, err)
+		return 0, false
+	}
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// CompleteFakePod completes a running pod whose run period has passed since it
+// started: its containers exit with code 0 and the pod succeeds. It returns whether
+// the pod completed, or else the time left until it does, 0 when it runs forever.
+func (r *SimReconciler) CompleteFakePod(ctx context.Context, pod *v1.Pod) (bool, time.Duration, error) {
+	period, ok := runPeriod(pod)
+	if !ok || pod.Status.Phase != v1.PodRunning || pod.Status.StartTime == nil {
+		return false, 0, nil
+	}
+	if remaining := time.Until(pod.Status.StartTime.Add(period)); remaining > 0 {
+		return false, remaining, nil
+	}
+
+	status := terminatingStatus(pod, true, metav1.Time{Time: time.Now()})
+	// Patch only the changed fields, so that concurrent status updates are kept
+	ops := []util.Ops{{Op: "add", Path: "/status/phase", Value: v1.PodSucceeded}}
+	if len(status.Conditions) > 0 {
+		ops = append(ops, util.Ops{Op: "add", Path: "/status/conditions", Value: status.Conditions})
+	}
+	if len(status.ContainerStatuses) > 0 {
+		ops = append(ops, util.Ops{Op: "add", Path: "/status/containerStatuses", Value: status.ContainerStatuses})
+	}
+	if err := r.Client.Status().Patch(ctx, pod, &util.Patch{PatchOps: ops}); err != nil {
+		klog.Errorf("Pod: %v/%v Patch Status Error: %v", pod.GetNamespace(), pod.GetName(), err)
+		return false, 0, err
+	}
+	r.UnprepareResourceClaims(ctx, pod)
+	return true, 0, nil
+}
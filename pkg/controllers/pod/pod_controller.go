@@ -3,15 +3,25 @@ package pod
 import (
 	"context"
 	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/metrics"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/report"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/shard"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/usage"
 	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 	"time"
 )
 
@@ -19,12 +29,81 @@ type SimReconciler struct {
 	Client    client.Client
 	ClientSet *kubernetes.Clientset
 	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	Usage     usage.Provider
+	// ShutdownPeriod is the simulated time containers take to stop, the
+	// grace period of the pod is used when nil.
+	ShutdownPeriod *time.Duration
+	// Shard selects the nodes whose pods are run by this replica, all of them when nil.
+	Shard *shard.Sharder
+	// Namespaces whose pods are run, all of them when empty.
+	Namespaces []string
+	// Workers is the number of pods synced in parallel.
+	Workers int
+	// Report records the placements of the pods, only the Prometheus histograms are
+	// observed when nil.
+	Report *report.Recorder
+
+	// deviceLocks serializes the allocation of the devices of each node between the
+	// workers.
+	deviceLocks util.KeyedMutex
 }
 
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups="",resources=pods/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=sim.k8s.io,resources=usageprofiles,verbs=get;list;watch
+
 func (r *SimReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&v1.Pod{}).
-		Complete(r)
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.Workers})
+	if r.Report != nil {
+		builder = builder.Watches(&source.Kind{Type: &v1.Pod{}}, r.observePods())
+	}
+	if r.Shard != nil {
+		// Pick up the pods of the nodes this replica took over.
+		rebalance := make(chan event.GenericEvent)
+		r.Shard.OnChange(func() {
+			podList, err := r.ClientSet.CoreV1().Pods("").List(metav1.ListOptions{
+				LabelSelector: node.ManagedPodSelector.String(),
+			})
+			if err != nil {
+				klog.Errorf("Rebalance List Pods Error: %v", err)
+				return
+			}
+			for i := range podList.Items {
+				pod := &podList.Items[i]
+				if pod.Spec.NodeName != "" && r.Shard.Owns(pod.Spec.NodeName) {
+					rebalance <- event.GenericEvent{Meta: pod, Object: pod}
+				}
+			}
+		})
+		builder = builder.Watches(&source.Channel{Source: rebalance}, &handler.EnqueueRequestForObject{})
+	}
+	return builder.Complete(r)
+}
+
+// observePods returns a handler that stamps the watch events of the managed pods
+// in the report, the pods are enqueued by the For watch.
+func (r *SimReconciler) observePods() handler.EventHandler {
+	observe := func(obj runtime.Object) {
+		if pod, ok := obj.(*v1.Pod); ok && node.IsManagedPod(pod) && util.WatchesNamespace(r.Namespaces, pod.GetNamespace()) {
+			r.Report.Observe(pod, time.Now())
+		}
+	}
+	return handler.Funcs{
+		CreateFunc: func(e event.CreateEvent, _ workqueue.RateLimitingInterface) {
+			observe(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+			observe(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent, _ workqueue.RateLimitingInterface) {
+			if pod, ok := e.Object.(*v1.Pod); ok {
+				r.Report.Forget(pod)
+			}
+		},
+	}
 }
 
 func (r *SimReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
@@ -43,25 +122,40 @@ func (r *SimReconciler) Reconcile(req ctrl.Request) (ctrl.Result, error) {
 		return ctrl.Result{}, nil
 	}
 
-	labels := pod.GetLabels()
-	if labels == nil {
-		return ctrl.Result{}, nil
-	}
-	if v, ok := labels[node.ManageLabelKey]; ok && v == node.ManageLabelValue {
+	if node.IsManagedPod(pod) && util.WatchesNamespace(r.Namespaces, pod.GetNamespace()) {
 		nodeName := pod.Spec.NodeName
-		if nodeName == "" {
+		if nodeName == "" || !r.Shard.Owns(nodeName) {
 			return ctrl.Result{}, nil
 		}
 
 		if pod.GetDeletionTimestamp() != nil {
-			gracePeriodSeconds := int64(0)
-			err = r.ClientSet.CoreV1().Pods(pod.GetNamespace()).Delete(pod.GetName(), &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriodSeconds})
-			if err != nil && !apierrors.IsNotFound(err) {
-				klog.Errorf("Delete Pod: %v Error: %v", req.String(), err)
+			return r.TerminateFakePod(ctx, pod.DeepCopy())
+		}
+
+		if isPodStarted(pod) {
+			completed, remaining, err := r.CompleteFakePod(ctx, pod.DeepCopy())
+			if completed || err != nil {
+				return ctrl.Result{}, err
+			}
+			result, err := r.CheckOOM(ctx, pod.DeepCopy())
+			if remaining > 0 && (result.RequeueAfter == 0 || remaining < result.RequeueAfter) {
+				result.RequeueAfter = remaining
 			}
+			return result, err
+		}
+		// Rejected at admission, or completed
+		if pod.Status.Phase == v1.PodFailed || pod.Status.Phase == v1.PodSucceeded {
 			return ctrl.Result{}, nil
 		}
-
+		if ok, err := r.AllocateFakeDevices(ctx, pod.DeepCopy()); !ok {
+			return ctrl.Result{}, err
+		}
+		if ok, err := r.PrepareResourceClaims(ctx, pod); !ok {
+			return ctrl.Result{RequeueAfter: ClaimRequeuePeriod}, err
+		}
+		if ok, err := r.MountVolumes(ctx, pod); !ok {
+			return ctrl.Result{RequeueAfter: VolumeRequeuePeriod}, err
+		}
 		r.SyncFakePod(pod.DeepCopy())
 	}
 
@@ -108,13 +202,9 @@ func (r *SimReconciler) SyncFakePod(pod *v1.Pod) {
 			Status:             v1.ConditionTrue,
 			Type:               v1.ContainersReady,
 		},
-		{
-			LastProbeTime:      updateTime,
-			LastTransitionTime: updateTime,
-			Status:             v1.ConditionTrue,
-			Type:               v1.PodScheduled,
-		},
 	}
+	scheduled := scheduledCondition(pod, updateTime)
+	conditions = append(conditions, scheduled)
 
 	podStatus := v1.PodStatus{
 		HostIP:            "10.0.0.1",
@@ -126,15 +216,60 @@ func (r *SimReconciler) SyncFakePod(pod *v1.Pod) {
 		ContainerStatuses: containerStatusList,
 	}
 
+	// Patch the fields one by one, so that status fields unknown to the typed Pod,
+	// such as resourceClaimStatuses, are kept.
 	ops := []util.Ops{
-		{
-			Op:    "replace",
-			Path:  "/status",
-			Value: podStatus,
-		},
+		{Op: "add", Path: "/status/hostIP", Value: podStatus.HostIP},
+		{Op: "add", Path: "/status/phase", Value: podStatus.Phase},
+		{Op: "add", Path: "/status/podIP", Value: podStatus.PodIP},
+		{Op: "add", Path: "/status/qosClass", Value: podStatus.QOSClass},
+		{Op: "add", Path: "/status/startTime", Value: podStatus.StartTime},
+		{Op: "add", Path: "/status/conditions", Value: podStatus.Conditions},
+		{Op: "add", Path: "/status/containerStatuses", Value: podStatus.ContainerStatuses},
 	}
+	// Patch decodes the patched pod into pod
+	starting := pod.Status.Phase != v1.PodRunning
+	start := time.Now()
 	err := r.Client.Status().Patch(context.TODO(), pod, &util.Patch{PatchOps: ops})
+	metrics.PodStatusPatchDuration.Observe(time.Since(start).Seconds())
 	if err != nil {
 		klog.Errorf("Pod: %v/%v Patch Status Error: %v", pod.GetNamespace(), pod.GetName(), err)
+		r.Recorder.Eventf(pod, v1.EventTypeWarning, FailedSyncEventReason, "Error syncing pod status: %v", err)
+		return
+	}
+
+	if starting {
+		r.Report.Started(pod, scheduled.LastTransitionTime.Time, updateTime.Time)
+		for _, container := range pod.Spec.Containers {
+			r.Recorder.Eventf(pod, v1.EventTypeNormal, PullingEventReason, "Pulling image %q", container.Image)
+			r.Recorder.Eventf(pod, v1.EventTypeNormal, PulledEventReason, "Successfully pulled image %q", container.Image)
+			r.Recorder.Eventf(pod, v1.EventTypeNormal, CreatedEventReason, "Created container %v", container.Name)
+			r.Recorder.Eventf(pod, v1.EventTypeNormal, StartedEventReason, "Started container %v", container.Name)
+		}
+	}
+}
+
+// isPodStarted reports whether the pod status has already been simulated, so that
+// it is not reset on every reconcile.
+func isPodStarted(pod *v1.Pod) bool {
+	if pod.Status.Phase != v1.PodRunning && pod.Status.Phase != v1.PodFailed {
+		return false
+	}
+	return len(pod.Status.ContainerStatuses) == len(pod.Spec.Containers)
+}
+
+// scheduledCondition returns the PodScheduled condition of a pod bound to the node, the
+// one set when the pod was bound is kept so that its time is the scheduling time.
+func scheduledCondition(pod *v1.Pod, updateTime metav1.Time) v1.PodCondition {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionTrue {
+			return condition
+		}
+	}
+	return v1.PodCondition{
+		LastProbeTime:      updateTime,
+		LastTransitionTime: updateTime,
+		Status:             v1.ConditionTrue,
+		Type:               v1.PodScheduled,
 	}
 }
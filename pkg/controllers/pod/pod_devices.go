@@ -0,0 +1,104 @@
+package pod
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+)
+
+// AllocateFakeDevices allocates the simulated devices requested by the pod on its
+// node and records their IDs in the AllocatedDevicesAnnotationKey annotation, like
+// a device plugin would. Pods the node has not enough healthy devices for are
+// rejected the way the kubelet admission does. It returns false when the pod must
+// not be started yet.
+func (r *SimReconciler) AllocateFakeDevices(ctx context.Context, pod *v1.Pod) (bool, error) {
+	if _, ok := pod.GetAnnotations()[node.AllocatedDevicesAnnotationKey]; ok {
+		return true, nil
+	}
+	// The other pods of the node must not pick the same devices meanwhile
+	unlock := r.deviceLocks.Lock(pod.Spec.NodeName)
+	defer unlock()
+
+	fakeNode := &v1.Node{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, fakeNode); err != nil {
+		klog.Errorf("Pod: %v/%v Get Node: %v Error: %v", pod.GetNamespace(), pod.GetName(), pod.Spec.NodeName, err)
+		return false, err
+	}
+	devices, err := node.NodeDevices(fakeNode)
+	if err != nil {
+		klog.Errorf("Pod: %v/%v Get Devices of Node: %v Error: %v", pod.GetNamespace(), pod.GetName(), pod.Spec.NodeName, err)
+		return false, err
+	}
+	if len(devices) == 0 {
+		return true, nil
+	}
+
+	podList, err := r.ClientSet.CoreV1().Pods("").List(metav1.ListOptions{
+		LabelSelector: node.ManagedPodSelector.String(),
+		FieldSelector: fields.Set{"spec.nodeName": pod.Spec.NodeName}.AsSelector().String(),
+	})
+	if err != nil {
+		klog.Errorf("Pod: %v/%v List Pods on Node: %v Error: %v", pod.GetNamespace(), pod.GetName(), pod.Spec.NodeName, err)
+		return false, err
+	}
+	usedBy := make([]v1.Pod, 0, len(podList.Items))
+	for _, other := range podList.Items {
+		if other.GetUID() != pod.GetUID() && other.Status.Phase != v1.PodSucceeded && other.Status.Phase != v1.PodFailed {
+			usedBy = append(usedBy, other)
+		}
+	}
+
+	allocated, ok := node.AllocateDevices(devices, pod, usedBy)
+	if !ok {
+		r.rejectPod(ctx, pod, "Allocate failed due to not enough healthy devices on node "+pod.Spec.NodeName)
+		return false, nil
+	}
+	if len(allocated) == 0 {
+		return true, nil
+	}
+
+	value, err := json.Marshal(allocated)
+	if err != nil {
+		return false, err
+	}
+	ops := []util.Ops{
+		{
+			Op:    "add",
+			Path:  "/metadata/annotations",
+			Value: map[string]string{node.AllocatedDevicesAnnotationKey: string(value)},
+		},
+	}
+	if pod.GetAnnotations() != nil {
+		ops[0].Path = "/metadata/annotations/" + util.EscapeJSONPointer(node.AllocatedDevicesAnnotationKey)
+		ops[0].Value = string(value)
+	}
+	if err := r.Client.Patch(ctx, pod, &util.Patch{PatchOps: ops}); err != nil {
+		klog.Errorf("Pod: %v/%v Patch Devices Error: %v", pod.GetNamespace(), pod.GetName(), err)
+		return false, err
+	}
+	return true, nil
+}
+
+// rejectPod fails a pod the node cannot admit.
+func (r *SimReconciler) rejectPod(ctx context.Context, pod *v1.Pod, message string) {
+	scheduled := scheduledCondition(pod, metav1.Now())
+	// Patch only the changed fields, so that the conditions and the rest of the status are kept
+	ops := []util.Ops{
+		{Op: "add", Path: "/status/phase", Value: v1.PodFailed},
+		{Op: "add", Path: "/status/reason", Value: UnexpectedAdmissionErrorReason},
+		{Op: "add", Path: "/status/message", Value: message},
+	}
+	if err := r.Client.Status().Patch(ctx, pod, &util.Patch{PatchOps: ops}); err != nil {
+		klog.Errorf("Pod: %v/%v Patch Status Error: %v", pod.GetNamespace(), pod.GetName(), err)
+		return
+	}
+	r.Recorder.Event(pod, v1.EventTypeWarning, UnexpectedAdmissionErrorReason, message)
+	r.Report.Rejected(pod, scheduled.LastTransitionTime.Time)
+}
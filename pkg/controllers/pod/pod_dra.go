@@ -0,0 +1,224 @@
+package pod
+
+import (
+	"context"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+)
+
+// +kubebuilder:rbac:groups=resource.k8s.io,resources=resourceclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups=resource.k8s.io,resources=resourceclaims/status,verbs=get;update;patch
+
+// podResourceClaims returns the names of the ResourceClaims of a pod. It returns
+// false while a claim generated from a ResourceClaimTemplate does not exist yet.
+func (r *SimReconciler) podResourceClaims(ctx context.Context, pod *v1.Pod) ([]string, bool, error) {
+	// The typed Pod in use has no resourceClaims fields.
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(v1.SchemeGroupVersion.WithKind("Pod"))
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: pod.GetNamespace(), Name: pod.GetName()}, obj); err != nil {
+		return nil, false, err
+	}
+	claims, _, _ := unstructured.NestedSlice(obj.Object, "spec", "resourceClaims")
+	if len(claims) == 0 {
+		return nil, true, nil
+	}
+
+	generated := make(map[string]string)
+	statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "resourceClaimStatuses")
+	for _, status := range statuses {
+		if status, ok := status.(map[string]interface{}); ok {
+			name, _, _ := unstructured.NestedString(status, "name")
+			claimName, _, _ := unstructured.NestedString(status, "resourceClaimName")
+			generated[name] = claimName
+		}
+	}
+
+	names := make([]string, 0, len(claims))
+	for _, claim := range claims {
+		claim, ok := claim.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(claim, "name")
+		claimName, _, _ := unstructured.NestedString(claim, "resourceClaimName")
+		if claimName == "" {
+			claimName = generated[name]
+		}
+		if claimName == "" {
+			return nil, false, nil
+		}
+		names = append(names, claimName)
+	}
+	return names, true, nil
+}
+
+// nodeDRADrivers returns the DRA drivers simulated on the node of a pod.
+func (r *SimReconciler) nodeDRADrivers(ctx context.Context, pod *v1.Pod) (map[string][]node.Device, error) {
+	fakeNode := &v1.Node{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, fakeNode); err != nil {
+		return nil, err
+	}
+	devices, err := node.NodeDevices(fakeNode)
+	if err != nil {
+		return nil, err
+	}
+	return node.DRADrivers(devices), nil
+}
+
+// getResourceClaim returns the ResourceClaim and whether it is reserved for the pod.
+func (r *SimReconciler) getResourceClaim(ctx context.Context, pod *v1.Pod, name string) (*unstructured.Unstructured, bool, error) {
+	claim := &unstructured.Unstructured{}
+	claim.SetGroupVersionKind(node.ResourceClaimGVK)
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: pod.GetNamespace(), Name: name}, claim); err != nil {
+		return nil, false, err
+	}
+	reservedFor, _, _ := unstructured.NestedSlice(claim.Object, "status", "reservedFor")
+	for _, consumer := range reservedFor {
+		if consumer, ok := consumer.(map[string]interface{}); ok {
+			if uid, _, _ := unstructured.NestedString(consumer, "uid"); uid == string(pod.GetUID()) {
+				return claim, true, nil
+			}
+		}
+	}
+	return claim, false, nil
+}
+
+// isSimulatedResult reports whether an allocation result is a device of a DRA
+// driver simulated on the node.
+func isSimulatedResult(result map[string]interface{}, nodeName string, drivers map[string][]node.Device) (driver, pool, device string, ok bool) {
+	driver, _, _ = unstructured.NestedString(result, "driver")
+	pool, _, _ = unstructured.NestedString(result, "pool")
+	device, _, _ = unstructured.NestedString(result, "device")
+	_, simulated := drivers[driver]
+	return driver, pool, device, simulated && pool == nodeName
+}
+
+// PrepareResourceClaims emulates the kubelet plugins of the DRA drivers simulated on
+// the node of the pod: the pod only starts once all its ResourceClaims are allocated
+// and reserved for it, and the devices of the simulated drivers are then reported
+// ready in the status of the claims. It returns false while the pod must wait.
+func (r *SimReconciler) PrepareResourceClaims(ctx context.Context, pod *v1.Pod) (bool, error) {
+	drivers, err := r.nodeDRADrivers(ctx, pod)
+	if err != nil || len(drivers) == 0 {
+		return err == nil, err
+	}
+	names, ready, err := r.podResourceClaims(ctx, pod)
+	if err != nil || !ready {
+		return false, err
+	}
+
+	for _, name := range names {
+		claim, reserved, err := r.getResourceClaim(ctx, pod, name)
+		if err != nil {
+			klog.Errorf("Pod: %v/%v Get ResourceClaim: %v Error: %v", pod.GetNamespace(), pod.GetName(), name, err)
+			return false, err
+		}
+		results, allocated, _ := unstructured.NestedSlice(claim.Object, "status", "allocation", "devices", "results")
+		if !reserved || !allocated {
+			return false, nil
+		}
+
+		statusDevices, _, _ := unstructured.NestedSlice(claim.Object, "status", "devices")
+		prepared := make(map[string]bool)
+		for _, status := range statusDevices {
+			if status, ok := status.(map[string]interface{}); ok {
+				driver, pool, device, _ := isSimulatedResult(status, pod.Spec.NodeName, drivers)
+				prepared[driver+"/"+pool+"/"+device] = true
+			}
+		}
+
+		changed := false
+		for _, result := range results {
+			result, ok := result.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			driver, pool, device, ok := isSimulatedResult(result, pod.Spec.NodeName, drivers)
+			if !ok || prepared[driver+"/"+pool+"/"+device] {
+				continue
+			}
+			statusDevices = append(statusDevices, map[string]interface{}{
+				"driver": driver,
+				"pool":   pool,
+				"device": device,
+				"conditions": []interface{}{
+					map[string]interface{}{
+						"type":               "Ready",
+						"status":             "True",
+						"reason":             PreparedReason,
+						"message":            "Device prepared by the simulated kubelet plugin",
+						"lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+					},
+				},
+			})
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		if err := unstructured.SetNestedSlice(claim.Object, statusDevices, "status", "devices"); err != nil {
+			return false, err
+		}
+		if err := r.Client.Status().Update(ctx, claim); err != nil {
+			klog.Errorf("Pod: %v/%v Prepare ResourceClaim: %v Error: %v", pod.GetNamespace(), pod.GetName(), name, err)
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// UnprepareResourceClaims removes the devices of the simulated DRA drivers from the
+// status of the claims of a pod that stopped, unless other pods still use the claim.
+func (r *SimReconciler) UnprepareResourceClaims(ctx context.Context, pod *v1.Pod) {
+	drivers, err := r.nodeDRADrivers(ctx, pod)
+	if err != nil || len(drivers) == 0 {
+		return
+	}
+	names, _, err := r.podResourceClaims(ctx, pod)
+	if err != nil {
+		return
+	}
+
+	for _, name := range names {
+		claim, _, err := r.getResourceClaim(ctx, pod, name)
+		if err != nil {
+			continue
+		}
+		reservedFor, _, _ := unstructured.NestedSlice(claim.Object, "status", "reservedFor")
+		inUse := false
+		for _, consumer := range reservedFor {
+			if consumer, ok := consumer.(map[string]interface{}); ok {
+				uid, _, _ := unstructured.NestedString(consumer, "uid")
+				inUse = inUse || uid != string(pod.GetUID())
+			}
+		}
+		if inUse {
+			continue
+		}
+
+		statusDevices, _, _ := unstructured.NestedSlice(claim.Object, "status", "devices")
+		remaining := make([]interface{}, 0, len(statusDevices))
+		for _, status := range statusDevices {
+			if status, ok := status.(map[string]interface{}); ok {
+				if _, _, _, simulated := isSimulatedResult(status, pod.Spec.NodeName, drivers); simulated {
+					continue
+				}
+			}
+			remaining = append(remaining, status)
+		}
+		if len(remaining) == len(statusDevices) {
+			continue
+		}
+		if err := unstructured.SetNestedSlice(claim.Object, remaining, "status", "devices"); err != nil {
+			continue
+		}
+		if err := r.Client.Status().Update(ctx, claim); err != nil {
+			klog.Errorf("Pod: %v/%v Unprepare ResourceClaim: %v Error: %v", pod.GetNamespace(), pod.GetName(), name, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package pod
+
+import (
+	"testing"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+)
+
+func TestIsSimulatedResult(t *testing.T) {
+	drivers := map[string][]node.Device{"gpu.nvidia.com": {{ID: "gpu-0"}}}
+	result := func(driver, pool string) map[string]interface{} {
+		return map[string]interface{}{"driver": driver, "pool": pool, "device": "gpu-0"}
+	}
+
+	tests := []struct {
+		name     string
+		result   map[string]interface{}
+		expected bool
+	}{
+		{name: "simulated driver of the node", result: result("gpu.nvidia.com", "fake-0"), expected: true},
+		{name: "pool of another node", result: result("gpu.nvidia.com", "fake-1")},
+		{name: "driver not simulated", result: result("nic.example.com", "fake-0")},
+		{name: "empty result", result: map[string]interface{}{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			driver, pool, device, ok := isSimulatedResult(test.result, "fake-0", drivers)
+			if ok != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, ok)
+			}
+			if len(test.result) > 0 && (driver != test.result["driver"] || pool != test.result["pool"] || device != test.result["device"]) {
+				t.Errorf("expected %v, got %v %v %v", test.result, driver, pool, device)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package pod
+
+import (
+	"context"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// hasMemoryLimit reports whether any container of the pod can be OOM killed.
+func hasMemoryLimit(pod *v1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		if _, ok := container.Resources.Limits[v1.ResourceMemory]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckOOM kills the containers whose simulated memory usage exceeds their limit.
+// Killed containers are restarted unless the restart policy is Never, in which case
+// the pod fails once none of its containers runs anymore.
+func (r *SimReconciler) CheckOOM(ctx context.Context, pod *v1.Pod) (ctrl.Result, error) {
+	if r.Usage == nil || !hasMemoryLimit(pod) {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	updateTime := metav1.Time{Time: now}
+	status := pod.Status.DeepCopy()
+	killed := false
+	running := 0
+
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		var containerStatus *v1.ContainerStatus
+		for j := range status.ContainerStatuses {
+			if status.ContainerStatuses[j].Name == container.Name {
+				containerStatus = &status.ContainerStatuses[j]
+			}
+		}
+		if containerStatus == nil || containerStatus.State.Running == nil {
+			continue
+		}
+
+		limit, ok := container.Resources.Limits[v1.ResourceMemory]
+		used := r.Usage.ContainerUsage(ctx, pod, container, now)[v1.ResourceMemory]
+		if !ok || used.Cmp(limit) <= 0 {
+			running++
+			continue
+		}
+
+		killed = true
+		terminated := v1.ContainerState{
+			Terminated: &v1.ContainerStateTerminated{
+				ExitCode:    OOMExitCode,
+				Reason:      OOMKilledReason,
+				StartedAt:   containerStatus.State.Running.StartedAt,
+				FinishedAt:  updateTime,
+				ContainerID: containerStatus.ContainerID,
+			},
+		}
+		r.Recorder.Eventf(pod, v1.EventTypeWarning, OOMKillingEventReason, "Memory cgroup out of memory: Killed container %v", container.Name)
+
+		if pod.Spec.RestartPolicy == v1.RestartPolicyNever {
+			containerStatus.State = terminated
+			containerStatus.Ready = false
+			continue
+		}
+		running++
+		containerStatus.LastTerminationState = terminated
+		containerStatus.State = v1.ContainerState{
+			Running: &v1.ContainerStateRunning{StartedAt: updateTime},
+		}
+		containerStatus.RestartCount++
+	}
+
+	if killed {
+		// Patch only the changed fields, so that concurrent status updates are kept
+		ops := []util.Ops{
+			{
+				Op:    "add",
+				Path:  "/status/containerStatuses",
+				Value: status.ContainerStatuses,
+			},
+		}
+		if running == 0 {
+			status.Phase = v1.PodFailed
+			for i := range status.Conditions {
+				if status.Conditions[i].Type == v1.PodReady || status.Conditions[i].Type == v1.ContainersReady {
+					status.Conditions[i].Status = v1.ConditionFalse
+					status.Conditions[i].LastTransitionTime = updateTime
+				}
+			}
+			ops = append(ops, util.Ops{Op: "add", Path: "/status/phase", Value: status.Phase})
+			if len(status.Conditions) > 0 {
+				ops = append(ops, util.Ops{Op: "add", Path: "/status/conditions", Value: status.Conditions})
+			}
+		}
+		if err := r.Client.Status().Patch(ctx, pod, &util.Patch{PatchOps: ops}); err != nil {
+			klog.Errorf("Pod: %v/%v Patch Status Error: %v", pod.GetNamespace(), pod.GetName(), err)
+			return ctrl.Result{}, err
+		}
+	}
+
+	if status.Phase == v1.PodFailed {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: OOMCheckPeriod}, nil
+}
@@ -0,0 +1,149 @@
+package pod
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/util"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// shutdownPeriod returns how long the containers of a terminating pod take to stop.
+// The pod annotation wins over the reconciler setting, and neither may exceed the
+// grace period the pod was deleted with.
+func (r *SimReconciler) shutdownPeriod(pod *v1.Pod) time.Duration {
+	grace := time.Duration(0)
+	if pod.GetDeletionGracePeriodSeconds() != nil {
+		grace = time.Duration(*pod.GetDeletionGracePeriodSeconds()) * time.Second
+	}
+
+	shutdown := grace
+	if r.ShutdownPeriod != nil {
+		shutdown = *r.ShutdownPeriod
+	}
+	if value, ok := pod.GetAnnotations()[ShutdownAnnotationKey]; ok {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			klog.Errorf("Pod: %v/%v Parse Annotation %v Error: %v", pod.GetNamespace(), pod.GetName(), ShutdownAnnotationKey, err)
+		} else {
+			shutdown = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if shutdown > grace {
+		shutdown = grace
+	}
+	if shutdown < 0 {
+		shutdown = 0
+	}
+	return shutdown
+}
+
+// TerminateFakePod emulates the kubelet shutting down a pod marked for deletion.
+// The pod turns not ready, its preStop hooks run, and once the shutdown period has
+// passed the containers are reported terminated and the pod is removed.
+func (r *SimReconciler) TerminateFakePod(ctx context.Context, pod *v1.Pod) (ctrl.Result, error) {
+	grace := int64(0)
+	if pod.GetDeletionGracePeriodSeconds() != nil {
+		grace = *pod.GetDeletionGracePeriodSeconds()
+	}
+	startTime := pod.GetDeletionTimestamp().Add(-time.Duration(grace) * time.Second)
+	deadline := startTime.Add(r.shutdownPeriod(pod))
+
+	if remaining := time.Until(deadline); remaining > 0 {
+		if isPodReady(pod) {
+			r.runPreStopHooks(pod)
+			r.patchTerminatingStatus(ctx, pod, false)
+		}
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
+	r.patchTerminatingStatus(ctx, pod, true)
+	r.UnprepareResourceClaims(ctx, pod)
+
+	gracePeriodSeconds := int64(0)
+	err := r.ClientSet.CoreV1().Pods(pod.GetNamespace()).Delete(pod.GetName(), &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriodSeconds})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Delete Pod: %v/%v Error: %v", pod.GetNamespace(), pod.GetName(), err)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+func (r *SimReconciler) runPreStopHooks(pod *v1.Pod) {
+	for _, container := range pod.Spec.Containers {
+		if container.Lifecycle != nil && container.Lifecycle.PreStop != nil {
+			klog.Infof("Pod: %v/%v Run PreStop Hook of Container: %v", pod.GetNamespace(), pod.GetName(), container.Name)
+		}
+		r.Recorder.Eventf(pod, v1.EventTypeNormal, KillingEventReason, "Stopping container %v", container.Name)
+	}
+}
+
+// patchTerminatingStatus marks the pod not ready. When terminated is true the
+// containers are also reported as exited.
+func (r *SimReconciler) patchTerminatingStatus(ctx context.Context, pod *v1.Pod, terminated bool) {
+	status := terminatingStatus(pod, terminated, metav1.Time{Time: time.Now()})
+	// Patch only the changed fields, so that concurrent status updates are kept
+	var ops []util.Ops
+	if len(status.Conditions) > 0 {
+		ops = append(ops, util.Ops{Op: "add", Path: "/status/conditions", Value: status.Conditions})
+	}
+	if len(status.ContainerStatuses) > 0 {
+		ops = append(ops, util.Ops{Op: "add", Path: "/status/containerStatuses", Value: status.ContainerStatuses})
+	}
+	if len(ops) == 0 {
+		return
+	}
+	if err := r.Client.Status().Patch(ctx, pod, &util.Patch{PatchOps: ops}); err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Pod: %v/%v Patch Status Error: %v", pod.GetNamespace(), pod.GetName(), err)
+	}
+}
+
+// terminatingStatus returns the status of the pod marked not ready at updateTime,
+// with its containers exited when terminated is true.
+func terminatingStatus(pod *v1.Pod, terminated bool, updateTime metav1.Time) *v1.PodStatus {
+	status := pod.Status.DeepCopy()
+
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == v1.PodReady || status.Conditions[i].Type == v1.ContainersReady {
+			status.Conditions[i].Status = v1.ConditionFalse
+			status.Conditions[i].LastTransitionTime = updateTime
+		}
+	}
+	for i := range status.ContainerStatuses {
+		containerStatus := &status.ContainerStatuses[i]
+		containerStatus.Ready = false
+		if terminated && containerStatus.State.Terminated == nil {
+			startedAt := updateTime
+			if containerStatus.State.Running != nil {
+				startedAt = containerStatus.State.Running.StartedAt
+			}
+			containerStatus.State = v1.ContainerState{
+				Terminated: &v1.ContainerStateTerminated{
+					ExitCode:    0,
+					Reason:      TerminatedReason,
+					StartedAt:   startedAt,
+					FinishedAt:  updateTime,
+					ContainerID: containerStatus.ContainerID,
+				},
+			}
+			started := false
+			containerStatus.Started = &started
+		}
+	}
+	return status
+}
+
+func isPodReady(pod *v1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
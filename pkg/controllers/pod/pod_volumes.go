@@ -0,0 +1,51 @@
+package pod
+
+import (
+	"context"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+)
+
+// MountVolumes emulates the volume manager of the kubelet: the pod only starts once
+// all its PersistentVolumeClaims are bound and the CSI volumes that require attaching
+// are reported attached to its node by their VolumeAttachments. It returns false
+// while the pod must wait.
+func (r *SimReconciler) MountVolumes(ctx context.Context, pod *v1.Pod) (bool, error) {
+	volumes, bound, err := node.PodCSIVolumes(ctx, r.Client, pod)
+	if err != nil {
+		klog.Errorf("Pod: %v/%v Get Volumes Error: %v", pod.GetNamespace(), pod.GetName(), err)
+		return false, err
+	}
+	if !bound {
+		return false, nil
+	}
+
+	for _, volume := range volumes {
+		required, err := node.AttachRequired(ctx, r.Client, volume.Driver)
+		if err != nil {
+			klog.Errorf("Pod: %v/%v Get CSIDriver: %v Error: %v", pod.GetNamespace(), pod.GetName(), volume.Driver, err)
+			return false, err
+		}
+		if !required {
+			continue
+		}
+		attachment := &storagev1.VolumeAttachment{}
+		name := node.VolumeAttachmentName(volume.VolumeHandle, volume.Driver, pod.Spec.NodeName)
+		err = r.Client.Get(ctx, types.NamespacedName{Name: name}, attachment)
+		if err != nil && apierrors.IsNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			klog.Errorf("Pod: %v/%v Get VolumeAttachment: %v Error: %v", pod.GetNamespace(), pod.GetName(), name, err)
+			return false, err
+		}
+		if !attachment.Status.Attached {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,123 @@
+package pod
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMountVolumes(t *testing.T) {
+	newClaim := func(name, driver string, phase v1.PersistentVolumeClaimPhase) []runtime.Object {
+		return []runtime.Object{
+			&v1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+				Spec:       v1.PersistentVolumeClaimSpec{VolumeName: "pv-" + name},
+				Status:     v1.PersistentVolumeClaimStatus{Phase: phase},
+			},
+			&v1.PersistentVolume{
+				ObjectMeta: metav1.ObjectMeta{Name: "pv-" + name},
+				Spec: v1.PersistentVolumeSpec{PersistentVolumeSource: v1.PersistentVolumeSource{
+					CSI: &v1.CSIPersistentVolumeSource{Driver: driver, VolumeHandle: "vol-" + name},
+				}},
+			},
+		}
+	}
+	newAttachment := func(claim, driver string, attached bool) *storagev1.VolumeAttachment {
+		pvName := "pv-" + claim
+		return &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: node.VolumeAttachmentName("vol-"+claim, driver, "fake-0")},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: driver,
+				NodeName: "fake-0",
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+			},
+			Status: storagev1.VolumeAttachmentStatus{Attached: attached},
+		}
+	}
+	const ebs, nfs = "ebs.csi.aws.com", "nfs.csi.k8s.io"
+	// The volumes of nfs don't require attaching
+	nfsDriver := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"attachRequired": false},
+	}}
+	nfsDriver.SetGroupVersionKind(node.CSIDriverGVK)
+	nfsDriver.SetName(nfs)
+
+	tests := []struct {
+		name     string
+		claims   []string
+		objects  []runtime.Object
+		expected bool
+	}{
+		{
+			name:     "no claims",
+			expected: true,
+		},
+		{
+			name:    "pending claim",
+			claims:  []string{"data"},
+			objects: newClaim("data", ebs, v1.ClaimPending),
+		},
+		{
+			name:    "bound claim without VolumeAttachment",
+			claims:  []string{"data"},
+			objects: newClaim("data", ebs, v1.ClaimBound),
+		},
+		{
+			name:    "bound claim attaching",
+			claims:  []string{"data"},
+			objects: append(newClaim("data", ebs, v1.ClaimBound), newAttachment("data", ebs, false)),
+		},
+		{
+			name:     "bound claim attached",
+			claims:   []string{"data"},
+			objects:  append(newClaim("data", ebs, v1.ClaimBound), newAttachment("data", ebs, true)),
+			expected: true,
+		},
+		{
+			name:     "bound claim of a driver without attach",
+			claims:   []string{"shared"},
+			objects:  newClaim("shared", nfs, v1.ClaimBound),
+			expected: true,
+		},
+		{
+			name:   "one claim not attached",
+			claims: []string{"shared", "data"},
+			objects: append(append(newClaim("shared", nfs, v1.ClaimBound), newClaim("data", ebs, v1.ClaimBound)...),
+				newAttachment("data", ebs, false)),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := &SimReconciler{Client: fake.NewFakeClientWithScheme(clientgoscheme.Scheme, test.objects...)}
+			if err := r.Client.Create(context.TODO(), nfsDriver.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+			pod := &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod"},
+				Spec:       v1.PodSpec{NodeName: "fake-0"},
+			}
+			for _, claim := range test.claims {
+				pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+					Name:         claim,
+					VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+				})
+			}
+			mounted, err := r.MountVolumes(context.TODO(), pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mounted != test.expected {
+				t.Errorf("expected mounted %v, got %v", test.expected, mounted)
+			}
+		})
+	}
+}
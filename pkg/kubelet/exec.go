@@ -0,0 +1,130 @@
+package kubelet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	"k8s.io/apimachinery/pkg/util/remotecommand"
+	"k8s.io/klog"
+)
+
+// StreamCreationTimeout is how long exec waits for the client to open its streams.
+const StreamCreationTimeout = 30 * time.Second
+
+var execProtocols = []string{
+	remotecommand.StreamProtocolV4Name,
+	remotecommand.StreamProtocolV3Name,
+	remotecommand.StreamProtocolV2Name,
+}
+
+// execStreams are the streams a client opens for an exec session.
+type execStreams struct {
+	errorStream  httpstream.Stream
+	stdinStream  httpstream.Stream
+	stdoutStream httpstream.Stream
+	stderrStream httpstream.Stream
+	resizeStream httpstream.Stream
+}
+
+// serveExec is a stub of the kubelet exec endpoint. Nothing runs, the command is
+// echoed to stdout and reported to have exited with 0.
+func (s *Server) serveExec(w http.ResponseWriter, req *http.Request, namespace, name, containerName string) {
+	pod, _, err := s.getPod(req.Context(), namespace, name, containerName)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		http.Error(w, fmt.Sprintf("pod %v/%v is not running", namespace, name), http.StatusBadRequest)
+		return
+	}
+
+	query := req.URL.Query()
+	tty := query.Get(v1.ExecTTYParam) == "1"
+	stdin := query.Get(v1.ExecStdinParam) == "1"
+	stdout := query.Get(v1.ExecStdoutParam) == "1"
+	stderr := query.Get(v1.ExecStderrParam) == "1" && !tty
+	command := query[v1.ExecCommandParam]
+
+	protocol, err := httpstream.Handshake(req, w, execProtocols)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	streamChan := make(chan httpstream.Stream, 5)
+	conn := spdy.NewResponseUpgrader().UpgradeResponse(w, req, func(stream httpstream.Stream, replySent <-chan struct{}) error {
+		streamChan <- stream
+		return nil
+	})
+	// The upgrader has already written the error response.
+	if conn == nil {
+		return
+	}
+	defer conn.Close()
+
+	expected := 1
+	for _, wanted := range []bool{stdin, stdout, stderr, tty && protocol != remotecommand.StreamProtocolV2Name} {
+		if wanted {
+			expected++
+		}
+	}
+	streams := &execStreams{}
+	timeout := time.After(StreamCreationTimeout)
+	for received := 0; received < expected; received++ {
+		select {
+		case stream := <-streamChan:
+			switch stream.Headers().Get(v1.StreamType) {
+			case v1.StreamTypeError:
+				streams.errorStream = stream
+			case v1.StreamTypeStdin:
+				streams.stdinStream = stream
+			case v1.StreamTypeStdout:
+				streams.stdoutStream = stream
+			case v1.StreamTypeStderr:
+				streams.stderrStream = stream
+			case v1.StreamTypeResize:
+				streams.resizeStream = stream
+			}
+		case <-timeout:
+			klog.Errorf("Pod: %v/%v Exec Error: timed out waiting for client streams", namespace, name)
+			return
+		}
+	}
+
+	if streams.stdinStream != nil {
+		go func() {
+			_, _ = io.Copy(ioutil.Discard, streams.stdinStream)
+		}()
+	}
+	if streams.resizeStream != nil {
+		go func() {
+			_, _ = io.Copy(ioutil.Discard, streams.resizeStream)
+		}()
+	}
+	if streams.stdoutStream != nil {
+		_, _ = streams.stdoutStream.Write([]byte(strings.Join(command, " ") + "\n"))
+		_ = streams.stdoutStream.Close()
+	}
+	if streams.stderrStream != nil {
+		_ = streams.stderrStream.Close()
+	}
+
+	if streams.errorStream != nil {
+		// Before v4 a closed error stream without a message means success.
+		if protocol == remotecommand.StreamProtocolV4Name {
+			status, _ := json.Marshal(&metav1.Status{Status: metav1.StatusSuccess})
+			_, _ = streams.errorStream.Write(status)
+		}
+		_ = streams.errorStream.Close()
+	}
+}
@@ -0,0 +1,250 @@
+package kubelet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// LogGeneratorAnnotationKey holds a LogGeneratorSpec in JSON, it generates the log of
+// every container of the pod.
+const LogGeneratorAnnotationKey = "sim.k8s.io/log-generator"
+
+// DefaultLogRate is the rate of the generators that never run out of lines, such as
+// the looping ones.
+const DefaultLogRate = 1.0
+
+type LogGeneratorType string
+
+const (
+	// LogLines writes fixed lines.
+	LogLines LogGeneratorType = "Lines"
+	// LogTemplate writes a text/template rendered for every line.
+	LogTemplate LogGeneratorType = "Template"
+	// LogConfigMap replays the lines of a ConfigMap key.
+	LogConfigMap LogGeneratorType = "ConfigMap"
+	// LogJSON writes structured logs, a JSON object with templated values per line.
+	LogJSON LogGeneratorType = "JSON"
+)
+
+// LogGeneratorSpec describes the log written by a simulated container.
+type LogGeneratorSpec struct {
+	Type LogGeneratorType `json:"type"`
+	// Lines of Lines.
+	Lines []string `json:"lines,omitempty"`
+	// Template of Template, see LogTemplateData for the available fields.
+	Template string `json:"template,omitempty"`
+	// ConfigMap replayed by ConfigMap, in the namespace of the pod.
+	ConfigMap *ConfigMapLogSource `json:"configMap,omitempty"`
+	// Fields of JSON, the values are templates like Template.
+	Fields map[string]string `json:"fields,omitempty"`
+	// RatePerSecond is the number of lines written per second. Lines and ConfigMap
+	// write all their lines at once when it is zero, unless Loop is set.
+	RatePerSecond float64 `json:"ratePerSecond,omitempty"`
+	// Loop repeats the lines of Lines and ConfigMap forever, at DefaultLogRate
+	// without RatePerSecond.
+	Loop bool `json:"loop,omitempty"`
+}
+
+// ConfigMapLogSource selects a key of a ConfigMap.
+type ConfigMapLogSource struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+// LogTemplateData is passed to the templates of Template and JSON.
+type LogTemplateData struct {
+	Seq       int
+	Time      time.Time
+	Namespace string
+	Pod       string
+	Container string
+	Node      string
+}
+
+// logSource computes the log lines of a container run. The seq-th line is written
+// at start + seq*interval, a source with a negative count never runs out.
+type logSource struct {
+	line     func(seq int, at time.Time) string
+	count    int
+	interval time.Duration
+}
+
+// available returns the number of lines written from start to now.
+func (l *logSource) available(start, now time.Time) int {
+	if now.Before(start) {
+		return 0
+	}
+	if l.interval <= 0 {
+		return l.count
+	}
+	n := int(now.Sub(start)/l.interval) + 1
+	if l.count >= 0 && n > l.count {
+		n = l.count
+	}
+	return n
+}
+
+// timeOf returns when the seq-th line is written.
+func (l *logSource) timeOf(start time.Time, seq int) time.Time {
+	return start.Add(time.Duration(seq) * l.interval)
+}
+
+// firstAfter returns the first line written at or after since.
+func (l *logSource) firstAfter(start, since time.Time) int {
+	if !since.After(start) {
+		return 0
+	}
+	if l.interval <= 0 {
+		return l.count
+	}
+	elapsed := since.Sub(start)
+	seq := int(elapsed / l.interval)
+	if elapsed%l.interval != 0 {
+		seq++
+	}
+	return seq
+}
+
+// linesSource writes lines once, or forever when loop is set.
+func linesSource(lines []string, rate float64, loop bool) *logSource {
+	if loop && rate <= 0 {
+		rate = DefaultLogRate
+	}
+	source := &logSource{
+		line: func(seq int, _ time.Time) string {
+			return lines[seq%len(lines)]
+		},
+		count: len(lines),
+	}
+	if len(lines) == 0 {
+		source.count = 0
+		return source
+	}
+	if rate > 0 {
+		source.interval = time.Duration(float64(time.Second) / rate)
+		if loop {
+			source.count = -1
+		}
+	}
+	return source
+}
+
+// defaultLogSource returns the log of containers without a generator: the
+// LogsAnnotationKey annotation of the pod, or a few lines about the container start.
+func defaultLogSource(pod *v1.Pod, container *v1.Container) *logSource {
+	if value, ok := pod.GetAnnotations()[LogsAnnotationKey]; ok {
+		lines := strings.Split(strings.TrimSuffix(value, "\n"), "\n")
+		if value == "" {
+			lines = nil
+		}
+		return linesSource(lines, 0, false)
+	}
+	return linesSource([]string{
+		fmt.Sprintf("Simulated container %v started from image %v", container.Name, container.Image),
+		fmt.Sprintf("Simulated container %v is running on node %v", container.Name, pod.Spec.NodeName),
+	}, 0, false)
+}
+
+// logSourceFor returns the log source of a container from the LogGeneratorAnnotationKey
+// annotation of its pod.
+func (s *Server) logSourceFor(ctx context.Context, pod *v1.Pod, container *v1.Container) (*logSource, error) {
+	value, ok := pod.GetAnnotations()[LogGeneratorAnnotationKey]
+	if !ok {
+		return defaultLogSource(pod, container), nil
+	}
+	spec := &LogGeneratorSpec{}
+	if err := json.Unmarshal([]byte(value), spec); err != nil {
+		return nil, fmt.Errorf("parse annotation %v: %v", LogGeneratorAnnotationKey, err)
+	}
+
+	data := func(seq int, at time.Time) *LogTemplateData {
+		return &LogTemplateData{
+			Seq:       seq,
+			Time:      at,
+			Namespace: pod.GetNamespace(),
+			Pod:       pod.GetName(),
+			Container: container.Name,
+			Node:      pod.Spec.NodeName,
+		}
+	}
+	rate := spec.RatePerSecond
+	if rate <= 0 {
+		rate = DefaultLogRate
+	}
+	interval := time.Duration(float64(time.Second) / rate)
+
+	switch spec.Type {
+	case LogLines:
+		return linesSource(spec.Lines, spec.RatePerSecond, spec.Loop), nil
+	case LogConfigMap:
+		if spec.ConfigMap == nil {
+			return nil, errors.New("configMap is required by the ConfigMap log generator")
+		}
+		configMap := &v1.ConfigMap{}
+		err := s.Client.Get(ctx, types.NamespacedName{Namespace: pod.GetNamespace(), Name: spec.ConfigMap.Name}, configMap)
+		if err != nil {
+			return nil, err
+		}
+		text, ok := configMap.Data[spec.ConfigMap.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %v not found in configmap %v", spec.ConfigMap.Key, spec.ConfigMap.Name)
+		}
+		var lines []string
+		if text = strings.TrimSuffix(text, "\n"); text != "" {
+			lines = strings.Split(text, "\n")
+		}
+		return linesSource(lines, spec.RatePerSecond, spec.Loop), nil
+	case LogTemplate:
+		tmpl, err := template.New("log").Parse(spec.Template)
+		if err != nil {
+			return nil, err
+		}
+		return &logSource{
+			line: func(seq int, at time.Time) string {
+				buf := &bytes.Buffer{}
+				if err := tmpl.Execute(buf, data(seq, at)); err != nil {
+					return err.Error()
+				}
+				return strings.TrimSuffix(buf.String(), "\n")
+			},
+			count:    -1,
+			interval: interval,
+		}, nil
+	case LogJSON:
+		fields := make(map[string]*template.Template, len(spec.Fields))
+		for key, value := range spec.Fields {
+			tmpl, err := template.New(key).Parse(value)
+			if err != nil {
+				return nil, err
+			}
+			fields[key] = tmpl
+		}
+		return &logSource{
+			line: func(seq int, at time.Time) string {
+				entry := make(map[string]string, len(fields))
+				for key, tmpl := range fields {
+					buf := &bytes.Buffer{}
+					if err := tmpl.Execute(buf, data(seq, at)); err != nil {
+						entry[key] = err.Error()
+						continue
+					}
+					entry[key] = buf.String()
+				}
+				line, _ := json.Marshal(entry)
+				return string(line)
+			},
+			count:    -1,
+			interval: interval,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown log generator type %q", spec.Type)
+}
@@ -0,0 +1,185 @@
+package kubelet
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// LogsAnnotationKey holds the canned log of the containers of a pod.
+const LogsAnnotationKey = "sim.k8s.io/logs"
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// containerStartTime returns when the named container last started, or the pod
+// start time if it is not running.
+func containerStartTime(pod *v1.Pod, containerName string) time.Time {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName && status.State.Running != nil {
+			return status.State.Running.StartedAt.Time
+		}
+	}
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time
+	}
+	return pod.GetCreationTimestamp().Time
+}
+
+// containerRun returns the start and, once the container terminated, the end of the
+// current or previous run of a container. end is zero while it runs.
+func containerRun(pod *v1.Pod, containerName string, previous bool) (start, end time.Time, err error) {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != containerName {
+			continue
+		}
+		state := status.State
+		if previous {
+			state = status.LastTerminationState
+			if state.Terminated == nil {
+				return start, end, apierrors.NewBadRequest(fmt.Sprintf("previous terminated container %q in pod %q not found", containerName, pod.GetName()))
+			}
+		}
+		switch {
+		case state.Running != nil:
+			return state.Running.StartedAt.Time, end, nil
+		case state.Terminated != nil:
+			return state.Terminated.StartedAt.Time, state.Terminated.FinishedAt.Time, nil
+		}
+		return start, end, apierrors.NewBadRequest(fmt.Sprintf("container %q in pod %q is waiting to start", containerName, pod.GetName()))
+	}
+	if previous {
+		return start, end, apierrors.NewBadRequest(fmt.Sprintf("previous terminated container %q in pod %q not found", containerName, pod.GetName()))
+	}
+	return containerStartTime(pod, containerName), end, nil
+}
+
+// logOptions are the query parameters of the kubelet containerLogs endpoint.
+type logOptions struct {
+	follow     bool
+	previous   bool
+	timestamps bool
+	tailLines  int
+	limitBytes int
+	sinceTime  time.Time
+}
+
+func parseLogOptions(query url.Values, now time.Time) (*logOptions, error) {
+	opts := &logOptions{
+		follow:     query.Get("follow") == "true",
+		previous:   query.Get("previous") == "true",
+		timestamps: query.Get("timestamps") == "true",
+		tailLines:  -1,
+		limitBytes: -1,
+	}
+	var err error
+	if value := query.Get("tailLines"); value != "" {
+		if opts.tailLines, err = strconv.Atoi(value); err != nil || opts.tailLines < 0 {
+			return nil, apierrors.NewBadRequest("invalid tailLines: " + value)
+		}
+	}
+	if value := query.Get("limitBytes"); value != "" {
+		if opts.limitBytes, err = strconv.Atoi(value); err != nil || opts.limitBytes <= 0 {
+			return nil, apierrors.NewBadRequest("invalid limitBytes: " + value)
+		}
+	}
+	if value := query.Get("sinceSeconds"); value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || seconds <= 0 {
+			return nil, apierrors.NewBadRequest("invalid sinceSeconds: " + value)
+		}
+		opts.sinceTime = now.Add(-time.Duration(seconds) * time.Second)
+	}
+	if value := query.Get("sinceTime"); value != "" {
+		if opts.sinceTime, err = time.Parse(time.RFC3339, value); err != nil {
+			return nil, apierrors.NewBadRequest("invalid sinceTime: " + value)
+		}
+	}
+	return opts, nil
+}
+
+// serveContainerLogs serves the generated log of a container like the kubelet
+// containerLogs endpoint, with follow, previous, timestamps, tailLines, limitBytes,
+// sinceSeconds and sinceTime.
+func (s *Server) serveContainerLogs(w http.ResponseWriter, req *http.Request, namespace, name, containerName string) {
+	ctx := req.Context()
+	now := time.Now()
+	pod, container, err := s.getPod(ctx, namespace, name, containerName)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	opts, err := parseLogOptions(req.URL.Query(), now)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	start, end, err := containerRun(pod, containerName, opts.previous)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	source, err := s.logSourceFor(ctx, pod, container)
+	if err != nil {
+		s.writeError(w, apierrors.NewBadRequest(err.Error()))
+		return
+	}
+
+	if !end.IsZero() {
+		now = end
+	}
+	available := source.available(start, now)
+	seq := source.firstAfter(start, opts.sinceTime)
+	if opts.tailLines >= 0 && available-opts.tailLines > seq {
+		seq = available - opts.tailLines
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	written := 0
+	writeLine := func(seq int) bool {
+		at := source.timeOf(start, seq)
+		line := source.line(seq, at) + "\n"
+		if opts.timestamps {
+			line = at.UTC().Format(time.RFC3339Nano) + " " + line
+		}
+		if opts.limitBytes > 0 && written+len(line) > opts.limitBytes {
+			line = line[:opts.limitBytes-written]
+		}
+		n, err := w.Write([]byte(line))
+		written += n
+		return err == nil && (opts.limitBytes <= 0 || written < opts.limitBytes)
+	}
+
+	for ; seq < available; seq++ {
+		if !writeLine(seq) {
+			return
+		}
+	}
+	if !opts.follow || !end.IsZero() {
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	for {
+		if flusher != nil {
+			flusher.Flush()
+		}
+		var next <-chan time.Time
+		if source.count < 0 || seq < source.count {
+			next = time.After(time.Until(source.timeOf(start, seq)))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-next:
+			if !writeLine(seq) {
+				return
+			}
+			seq++
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package kubelet
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/usage"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Server is a kubelet-like HTTPS server shared by all fake nodes. It serves the
+// container logs, exec, stats, healthz and pods endpoints of the kubelet API, so
+// that kubectl logs, kubectl exec and tools calling the kubelet work against the
+// fake nodes.
+//
+// The endpoints of a pod are resolved from the pod itself. The node of /pods and
+// /stats/summary is taken from the /nodes/<name> path prefix, the Host header or
+// the TLS server name, in that order. A Host or server name that is an IP is
+// matched against the addresses of the managed nodes, indexed by NodeAddressField.
+type Server struct {
+	Client client.Client
+	Usage  usage.Provider
+	// Addr is the address the HTTPS server listens on.
+	Addr string
+	// CertDir holds tls.crt and tls.key, a self-signed certificate is used when empty.
+	CertDir string
+}
+
+// Start runs the server until stop is closed, it implements manager.Runnable.
+func (s *Server) Start(stop <-chan struct{}) error {
+	certificate, err := s.certificate()
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:      s.Addr,
+		Handler:   s,
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{certificate}},
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		klog.Infof("Starting Kubelet Server on %v", s.Addr)
+		errChan <- server.ListenAndServeTLS("", "")
+	}()
+
+	select {
+	case <-stop:
+		return server.Shutdown(context.Background())
+	case err := <-errChan:
+		return err
+	}
+}
+
+// NodeAddressField indexes the managed nodes by the addresses in their status.
+const NodeAddressField = "status.addresses"
+
+// IndexNodeAddresses registers the NodeAddressField index the server resolves IPs with.
+func IndexNodeAddresses(indexer client.FieldIndexer) error {
+	return indexer.IndexField(&v1.Node{}, NodeAddressField, func(obj runtime.Object) []string {
+		fakeNode, ok := obj.(*v1.Node)
+		if !ok || fakeNode.GetLabels()[node.ManageLabelKey] != node.ManageLabelValue {
+			return nil
+		}
+		addresses := make([]string, 0, len(fakeNode.Status.Addresses))
+		for _, address := range fakeNode.Status.Addresses {
+			addresses = append(addresses, address.Address)
+		}
+		return addresses
+	})
+}
+
+func (s *Server) certificate() (tls.Certificate, error) {
+	if s.CertDir != "" {
+		return tls.LoadX509KeyPair(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	}
+	certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey("node-simulator-kubelet", nil, nil)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.Trim(req.URL.Path, "/")
+	nodeName := ""
+	if strings.HasPrefix(path, "nodes/") {
+		parts := strings.SplitN(strings.TrimPrefix(path, "nodes/"), "/", 2)
+		nodeName = parts[0]
+		path = ""
+		if len(parts) == 2 {
+			path = parts[1]
+		}
+	}
+
+	parts := strings.Split(path, "/")
+	switch {
+	case path == "healthz":
+		_, _ = w.Write([]byte("ok"))
+	case path == "pods" && req.Method == http.MethodGet:
+		s.servePods(w, req, nodeName)
+	case path == "stats/summary" && req.Method == http.MethodGet:
+		s.serveSummary(w, req, nodeName)
+	case len(parts) == 4 && parts[0] == "containerLogs" && req.Method == http.MethodGet:
+		s.serveContainerLogs(w, req, parts[1], parts[2], parts[3])
+	case len(parts) == 4 && parts[0] == "exec":
+		s.serveExec(w, req, parts[1], parts[2], parts[3])
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// resolveNode returns the fake node a node scoped request is meant for.
+func (s *Server) resolveNode(ctx context.Context, req *http.Request, nodeName string) (*v1.Node, error) {
+	candidates := []string{nodeName}
+	if nodeName == "" {
+		host := req.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		candidates = []string{host}
+		if req.TLS != nil {
+			candidates = append(candidates, req.TLS.ServerName)
+		}
+	}
+
+	for _, name := range candidates {
+		if name == "" {
+			continue
+		}
+		if net.ParseIP(name) != nil {
+			fakeNode, err := s.nodeByAddress(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			if fakeNode != nil {
+				return fakeNode, nil
+			}
+			continue
+		}
+		fakeNode := &v1.Node{}
+		if err := s.Client.Get(ctx, types.NamespacedName{Name: name}, fakeNode); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if fakeNode.GetLabels()[node.ManageLabelKey] == node.ManageLabelValue {
+			return fakeNode, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(v1.Resource("nodes"), nodeName)
+}
+
+// nodeByAddress returns the managed node with the address, nil when no node or
+// several nodes, such as the nodes of one NodeSimulator, have it.
+func (s *Server) nodeByAddress(ctx context.Context, address string) (*v1.Node, error) {
+	nodeList := &v1.NodeList{}
+	err := s.Client.List(ctx, nodeList,
+		client.MatchingLabels{node.ManageLabelKey: node.ManageLabelValue},
+		client.MatchingFields{NodeAddressField: address})
+	if err != nil {
+		return nil, err
+	}
+	var found *v1.Node
+	for i := range nodeList.Items {
+		for _, nodeAddress := range nodeList.Items[i].Status.Addresses {
+			if nodeAddress.Address != address {
+				continue
+			}
+			if found != nil {
+				return nil, nil
+			}
+			found = &nodeList.Items[i]
+			break
+		}
+	}
+	return found, nil
+}
+
+// getPod returns the managed pod with the given name, and the named container of it.
+func (s *Server) getPod(ctx context.Context, namespace, name, containerName string) (*v1.Pod, *v1.Container, error) {
+	pod := &v1.Pod{}
+	if err := s.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, pod); err != nil {
+		return nil, nil, err
+	}
+	if !node.IsManagedPod(pod) || pod.Spec.NodeName == "" {
+		return nil, nil, apierrors.NewNotFound(v1.Resource("pods"), name)
+	}
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == containerName {
+			return pod, &pod.Spec.Containers[i], nil
+		}
+	}
+	return nil, nil, apierrors.NewBadRequest("container " + containerName + " is not valid for pod " + name)
+}
+
+// nodePods lists the managed pods bound to a node.
+func (s *Server) nodePods(ctx context.Context, nodeName string) ([]v1.Pod, error) {
+	podList := &v1.PodList{}
+	err := s.Client.List(ctx, podList, client.MatchingLabelsSelector{Selector: node.ManagedPodSelector})
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]v1.Pod, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName == nodeName {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func (s *Server) servePods(w http.ResponseWriter, req *http.Request, nodeName string) {
+	ctx := req.Context()
+	fakeNode, err := s.resolveNode(ctx, req, nodeName)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	pods, err := s.nodePods(ctx, fakeNode.GetName())
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	s.writeJSON(w, &v1.PodList{
+		TypeMeta: metav1.TypeMeta{Kind: "PodList", APIVersion: "v1"},
+		Items:    pods,
+	})
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	if status, ok := err.(apierrors.APIStatus); ok {
+		code = int(status.Status().Code)
+	}
+	http.Error(w, err.Error(), code)
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		klog.Errorf("Kubelet Server Write Response Error: %v", err)
+	}
+}
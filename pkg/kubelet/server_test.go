@@ -0,0 +1,77 @@
+package kubelet
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestResolveNode(t *testing.T) {
+	newNode := func(name string, managed bool, addresses ...string) *v1.Node {
+		fakeNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if managed {
+			fakeNode.Labels = map[string]string{node.ManageLabelKey: node.ManageLabelValue}
+		}
+		for _, address := range addresses {
+			fakeNode.Status.Addresses = append(fakeNode.Status.Addresses, v1.NodeAddress{Type: v1.NodeInternalIP, Address: address})
+		}
+		return fakeNode
+	}
+	s := &Server{Client: fake.NewFakeClientWithScheme(clientgoscheme.Scheme,
+		newNode("fake-0", true, "10.0.0.1"),
+		newNode("fake-1", true, "10.0.0.2"),
+		newNode("fake-2", true, "10.0.0.2"),
+		newNode("real", false, "10.0.0.3"),
+	)}
+
+	tests := []struct {
+		name       string
+		nodeName   string
+		host       string
+		serverName string
+		expected   string
+	}{
+		{name: "path", nodeName: "fake-0", host: "fake-1", expected: "fake-0"},
+		{name: "host", host: "fake-1", expected: "fake-1"},
+		{name: "host with port", host: "fake-1:10250", expected: "fake-1"},
+		{name: "server name", host: "simulator", serverName: "fake-1", expected: "fake-1"},
+		{name: "host before server name", host: "fake-0", serverName: "fake-1", expected: "fake-0"},
+		{name: "ip", host: "10.0.0.1:10250", expected: "fake-0"},
+		{name: "ip server name", host: "simulator", serverName: "10.0.0.1", expected: "fake-0"},
+		{name: "ip shared by several nodes", host: "10.0.0.2"},
+		{name: "ip of a real node", host: "10.0.0.3"},
+		{name: "real node", host: "real"},
+		{name: "unknown node", nodeName: "fake-9"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/pods", nil)
+			req.Host = test.host
+			if test.serverName != "" {
+				req.TLS = &tls.ConnectionState{ServerName: test.serverName}
+			}
+			fakeNode, err := s.resolveNode(context.TODO(), req, test.nodeName)
+			if test.expected == "" {
+				if !apierrors.IsNotFound(err) {
+					t.Fatalf("expected NotFound, got %v, %v", fakeNode, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fakeNode.GetName() != test.expected {
+				t.Errorf("expected node %v, got %v", test.expected, fakeNode.GetName())
+			}
+		})
+	}
+}
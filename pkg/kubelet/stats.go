@@ -0,0 +1,110 @@
+package kubelet
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/usage"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// cpuStats converts a simulated CPU usage, assumed constant since start, to CPUStats.
+func cpuStats(used v1.ResourceList, start, now time.Time) *CPUStats {
+	quantity := used[v1.ResourceCPU]
+	nanoCores := uint64(quantity.MilliValue()) * uint64(time.Millisecond)
+	coreNanoSeconds := uint64(0)
+	if !start.IsZero() && start.Before(now) {
+		coreNanoSeconds = uint64(float64(nanoCores) * now.Sub(start).Seconds())
+	}
+	return &CPUStats{
+		Time:                 metav1.Time{Time: now},
+		UsageNanoCores:       &nanoCores,
+		UsageCoreNanoSeconds: &coreNanoSeconds,
+	}
+}
+
+// memoryStats converts a simulated memory usage to MemoryStats, capacity is only
+// used for the available bytes and may be nil.
+func memoryStats(used v1.ResourceList, capacity v1.ResourceList, now time.Time) *MemoryStats {
+	quantity := used[v1.ResourceMemory]
+	usageBytes := uint64(quantity.Value())
+	stats := &MemoryStats{
+		Time:            metav1.Time{Time: now},
+		UsageBytes:      &usageBytes,
+		WorkingSetBytes: &usageBytes,
+	}
+	if total, ok := capacity[v1.ResourceMemory]; ok && uint64(total.Value()) > usageBytes {
+		availableBytes := uint64(total.Value()) - usageBytes
+		stats.AvailableBytes = &availableBytes
+	}
+	return stats
+}
+
+func (s *Server) serveSummary(w http.ResponseWriter, req *http.Request, nodeName string) {
+	ctx := req.Context()
+	now := time.Now()
+	fakeNode, err := s.resolveNode(ctx, req, nodeName)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	pods, err := s.nodePods(ctx, fakeNode.GetName())
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	nodeStart := fakeNode.GetCreationTimestamp().Time
+	summary := &Summary{
+		Node: NodeStats{
+			NodeName:  fakeNode.GetName(),
+			StartTime: metav1.Time{Time: nodeStart},
+		},
+		Pods: make([]PodStats, 0, len(pods)),
+	}
+
+	nodeUsage := v1.ResourceList{}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		podStart := pod.GetCreationTimestamp().Time
+		if pod.Status.StartTime != nil {
+			podStart = pod.Status.StartTime.Time
+		}
+
+		podUsage := v1.ResourceList{}
+		containers := make([]ContainerStats, 0, len(pod.Spec.Containers))
+		for j := range pod.Spec.Containers {
+			container := &pod.Spec.Containers[j]
+			containerStart := containerStartTime(pod, container.Name)
+			used := s.Usage.ContainerUsage(ctx, pod, container, now)
+			usage.AddResourceList(podUsage, used)
+			containers = append(containers, ContainerStats{
+				Name:      container.Name,
+				StartTime: metav1.Time{Time: containerStart},
+				CPU:       cpuStats(used, containerStart, now),
+				Memory:    memoryStats(used, nil, now),
+			})
+		}
+		usage.AddResourceList(nodeUsage, podUsage)
+
+		summary.Pods = append(summary.Pods, PodStats{
+			PodRef: PodReference{
+				Name:      pod.GetName(),
+				Namespace: pod.GetNamespace(),
+				UID:       string(pod.GetUID()),
+			},
+			StartTime:  metav1.Time{Time: podStart},
+			Containers: containers,
+			CPU:        cpuStats(podUsage, podStart, now),
+			Memory:     memoryStats(podUsage, nil, now),
+		})
+	}
+	summary.Node.CPU = cpuStats(nodeUsage, nodeStart, now)
+	summary.Node.Memory = memoryStats(nodeUsage, fakeNode.Status.Capacity, now)
+
+	s.writeJSON(w, summary)
+}
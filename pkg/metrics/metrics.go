@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const Namespace = "nodesimulator"
+
+var (
+	// SimulatedNodes is the number of fake nodes of each NodeSimulator.
+	SimulatedNodes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "simulated_nodes",
+		Help:      "Number of fake nodes managed by each NodeSimulator.",
+	}, []string{"namespace", "name"})
+
+	// HeartbeatDuration is how long Updater.SyncNode takes for one node.
+	HeartbeatDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "node_heartbeat_duration_seconds",
+		Help:      "Latency of one fake node heartbeat, covering status, allocatable and lease.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	})
+
+	// HeartbeatErrors counts the failed steps of Updater.SyncNode.
+	HeartbeatErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "node_heartbeat_errors_total",
+		Help:      "Number of failed fake node heartbeat steps.",
+	}, []string{"step"})
+
+	// LeaseRenewLag is the time between two renewals of a node lease.
+	LeaseRenewLag = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "node_lease_renew_lag_seconds",
+		Help:      "Time elapsed since the previous renewal when a fake node lease is renewed.",
+		Buckets:   prometheus.LinearBuckets(5, 5, 12),
+	})
+
+	// PodStatusPatchDuration is how long a managed pod status patch takes.
+	PodStatusPatchDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "pod_status_patch_duration_seconds",
+		Help:      "Latency of managed pod status patches.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	})
+
+	// PodSchedulingLatency is the time from the creation of a managed pod to its binding.
+	PodSchedulingLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "pod_scheduling_latency_seconds",
+		Help:      "Time from the creation of a managed pod to its binding to a fake node.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 16),
+	})
+
+	// PodStartupLatency is the time from the binding of a managed pod to its start.
+	PodStartupLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "pod_startup_latency_seconds",
+		Help:      "Time from the binding of a managed pod to a fake node to the pod running.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 16),
+	})
+
+	// PodPlacements counts the managed pods bound to the fake nodes by outcome.
+	PodPlacements = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "pod_placements_total",
+		Help:      "Number of managed pods bound to fake nodes, by outcome: running or rejected.",
+	}, []string{"outcome"})
+
+	// APIRequests counts the requests the simulator sends to the API server.
+	APIRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "api_requests_total",
+		Help:      "Number of API server requests sent by the simulator, by verb, resource and status code.",
+	}, []string{"verb", "resource", "code"})
+)
+
+// Placement outcomes
+const (
+	OutcomeRunning  = "running"
+	OutcomeRejected = "rejected"
+)
+
+// Heartbeat steps
+const (
+	StepConditions  = "conditions"
+	StepAllocatable = "allocatable"
+	StepLease       = "lease"
+	StepSCV         = "scv"
+	StepDRA         = "dra"
+	StepVolumes     = "volumes"
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		SimulatedNodes,
+		HeartbeatDuration,
+		HeartbeatErrors,
+		LeaseRenewLag,
+		PodStatusPatchDuration,
+		PodSchedulingLatency,
+		PodStartupLatency,
+		PodPlacements,
+		APIRequests,
+	)
+}
@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// InstrumentTransport counts every request going through rt in APIRequests.
+// It is meant to be used as the WrapTransport of the rest.Config.
+func InstrumentTransport(rt http.RoundTripper) http.RoundTripper {
+	return &instrumentedTransport{next: rt}
+}
+
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	code := "<error>"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	verb, resource := requestVerbResource(req)
+	APIRequests.WithLabelValues(verb, resource, code).Inc()
+	return resp, err
+}
+
+// requestVerbResource derives the Kubernetes verb and resource of an API request
+// from its method and path, e.g. /api/v1/namespaces/ns/pods/name/status.
+func requestVerbResource(req *http.Request) (string, string) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		parts = parts[3:]
+	default:
+		return strings.ToLower(req.Method), "<other>"
+	}
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		parts = parts[2:]
+	}
+	if len(parts) == 0 {
+		return strings.ToLower(req.Method), "<other>"
+	}
+
+	resource := parts[0]
+	if len(parts) >= 3 {
+		resource += "/" + parts[2]
+	}
+	named := len(parts) >= 2
+
+	switch req.Method {
+	case http.MethodGet:
+		if req.URL.Query().Get("watch") == "true" {
+			return "watch", resource
+		}
+		if named {
+			return "get", resource
+		}
+		return "list", resource
+	case http.MethodPost:
+		return "create", resource
+	case http.MethodPut:
+		return "update", resource
+	case http.MethodPatch:
+		return "patch", resource
+	case http.MethodDelete:
+		if named {
+			return "delete", resource
+		}
+		return "deletecollection", resource
+	}
+	return strings.ToLower(req.Method), resource
+}
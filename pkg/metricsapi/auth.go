@@ -0,0 +1,207 @@
+package metricsapi
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+)
+
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// The API server publishes the front-proxy client CA and the request headers it sets
+// for the aggregated API servers in this ConfigMap.
+const (
+	AuthenticationConfigMapNamespace = "kube-system"
+	AuthenticationConfigMapName      = "extension-apiserver-authentication"
+)
+
+// RequestHeaderAuth authenticates the requests proxied by the API server: they carry
+// a client certificate signed by the front-proxy CA, and the user in request headers.
+type RequestHeaderAuth struct {
+	ClientCAs *x509.CertPool
+	// AllowedNames are the common names of the proxy certificates, any when empty.
+	AllowedNames        []string
+	UsernameHeaders     []string
+	GroupHeaders        []string
+	ExtraHeaderPrefixes []string
+}
+
+// User is the user a request is made for.
+type User struct {
+	Name   string
+	Groups []string
+	Extra  map[string][]string
+}
+
+// requestHeaderAuth reads the RequestHeaderAuth from the authentication ConfigMap.
+func (s *Server) requestHeaderAuth(ctx context.Context) (*RequestHeaderAuth, error) {
+	configMap := &v1.ConfigMap{}
+	key := types.NamespacedName{Namespace: AuthenticationConfigMapNamespace, Name: AuthenticationConfigMapName}
+	if err := s.Client.Get(ctx, key, configMap); err != nil {
+		return nil, err
+	}
+	return ParseRequestHeaderAuth(configMap)
+}
+
+// ParseRequestHeaderAuth returns the RequestHeaderAuth of the authentication ConfigMap.
+func ParseRequestHeaderAuth(configMap *v1.ConfigMap) (*RequestHeaderAuth, error) {
+	clientCA := configMap.Data["requestheader-client-ca-file"]
+	if clientCA == "" {
+		return nil, errors.New("no requestheader-client-ca-file in " + configMap.GetNamespace() + "/" + configMap.GetName())
+	}
+	auth := &RequestHeaderAuth{ClientCAs: x509.NewCertPool()}
+	if !auth.ClientCAs.AppendCertsFromPEM([]byte(clientCA)) {
+		return nil, errors.New("invalid requestheader-client-ca-file")
+	}
+	lists := map[string]*[]string{
+		"requestheader-allowed-names":        &auth.AllowedNames,
+		"requestheader-username-headers":     &auth.UsernameHeaders,
+		"requestheader-group-headers":        &auth.GroupHeaders,
+		"requestheader-extra-headers-prefix": &auth.ExtraHeaderPrefixes,
+	}
+	for key, list := range lists {
+		if value := configMap.Data[key]; value != "" {
+			if err := json.Unmarshal([]byte(value), list); err != nil {
+				return nil, errors.New("invalid " + key + ": " + err.Error())
+			}
+		}
+	}
+	return auth, nil
+}
+
+// tlsConfig verifies the client certificates against the current front-proxy CA, so
+// that a rotated CA is picked up without restarting.
+func (s *Server) tlsConfig(certificate tls.Certificate) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			config := &tls.Config{Certificates: []tls.Certificate{certificate}}
+			auth, err := s.requestHeaderAuth(context.Background())
+			if err != nil {
+				// Without the CA no client is authenticated, the health checks still pass
+				klog.Errorf("Metrics API Get Request Header Auth Error: %v", err)
+				return config, nil
+			}
+			// The health checks come without a certificate
+			config.ClientAuth = tls.VerifyClientCertIfGiven
+			config.ClientCAs = auth.ClientCAs
+			return config, nil
+		},
+	}
+}
+
+// Authenticate returns the user of a request proxied by the API server. The client
+// certificate was verified against ClientCAs during the handshake.
+func (a *RequestHeaderAuth) Authenticate(req *http.Request) (*User, bool) {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+	if len(a.AllowedNames) > 0 {
+		commonName := req.TLS.VerifiedChains[0][0].Subject.CommonName
+		allowed := false
+		for _, name := range a.AllowedNames {
+			allowed = allowed || name == commonName
+		}
+		if !allowed {
+			return nil, false
+		}
+	}
+
+	user := &User{Extra: make(map[string][]string)}
+	for _, header := range a.UsernameHeaders {
+		if user.Name = req.Header.Get(header); user.Name != "" {
+			break
+		}
+	}
+	if user.Name == "" {
+		return nil, false
+	}
+	for _, header := range a.GroupHeaders {
+		user.Groups = append(user.Groups, req.Header[http.CanonicalHeaderKey(header)]...)
+	}
+	for _, prefix := range a.ExtraHeaderPrefixes {
+		for header, values := range req.Header {
+			if strings.HasPrefix(strings.ToLower(header), strings.ToLower(prefix)) {
+				key := strings.ToLower(header[len(prefix):])
+				user.Extra[key] = append(user.Extra[key], values...)
+			}
+		}
+	}
+	return user, true
+}
+
+// AccessReview returns the SubjectAccessReview of a user reading path, the path of
+// the request below /apis/metrics.k8s.io/v1beta1 split on slashes, or nil for a
+// discovery request.
+func AccessReview(user *User, parts []string) *authorizationv1.SubjectAccessReview {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, values := range user.Extra {
+		extra[key] = values
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Name,
+			Groups: user.Groups,
+			Extra:  extra,
+		},
+	}
+	attributes := &authorizationv1.ResourceAttributes{
+		Group:   GroupVersion.Group,
+		Version: GroupVersion.Version,
+		Verb:    "list",
+	}
+	switch {
+	case len(parts) == 1 && (parts[0] == "nodes" || parts[0] == "pods"):
+		attributes.Resource = parts[0]
+	case len(parts) == 2 && parts[0] == "nodes":
+		attributes.Resource, attributes.Name, attributes.Verb = "nodes", parts[1], "get"
+	case len(parts) == 3 && parts[0] == "namespaces" && parts[2] == "pods":
+		attributes.Resource, attributes.Namespace = "pods", parts[1]
+	case len(parts) == 4 && parts[0] == "namespaces" && parts[2] == "pods":
+		attributes.Resource, attributes.Namespace, attributes.Name, attributes.Verb = "pods", parts[1], parts[3], "get"
+	default:
+		return nil
+	}
+	review.Spec.ResourceAttributes = attributes
+	return review
+}
+
+// authorize authenticates the request and asks the API server whether the user may
+// read parts, writing the error response when it may not.
+func (s *Server) authorize(w http.ResponseWriter, req *http.Request, parts []string) bool {
+	auth, err := s.requestHeaderAuth(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	user, ok := auth.Authenticate(req)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	review := AccessReview(user, parts)
+	if review == nil {
+		// Discovery is open to every authenticated user, like on the API server
+		return true
+	}
+	if err := s.Client.Create(req.Context(), review); err != nil {
+		klog.Errorf("Metrics API Create SubjectAccessReview Error: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	if !review.Status.Allowed {
+		attributes := review.Spec.ResourceAttributes
+		http.Error(w, "user \""+user.Name+"\" cannot "+attributes.Verb+" "+attributes.Resource+"."+attributes.Group, http.StatusForbidden)
+		return false
+	}
+	return true
+}
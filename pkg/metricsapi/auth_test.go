@@ -0,0 +1,192 @@
+package metricsapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newAuthConfigMap(t *testing.T, data map[string]string) *v1.ConfigMap {
+	caPEM, _, err := cert.GenerateSelfSignedCertKey("front-proxy-ca", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := map[string]string{"requestheader-client-ca-file": string(caPEM)}
+	for key, value := range data {
+		all[key] = value
+	}
+	return &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: AuthenticationConfigMapNamespace, Name: AuthenticationConfigMapName},
+		Data:       all,
+	}
+}
+
+// newProxiedRequest returns a request whose client certificate of commonName was verified.
+func newProxiedRequest(path, commonName string, headers map[string][]string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: pkix.Name{CommonName: commonName}}}}}
+	for key, values := range headers {
+		req.Header[http.CanonicalHeaderKey(key)] = values
+	}
+	return req
+}
+
+func TestParseRequestHeaderAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    map[string]string
+		noCA    bool
+		invalid bool
+	}{
+		{name: "lists", data: map[string]string{"requestheader-allowed-names": `["front-proxy-client"]`}},
+		{name: "no client CA", noCA: true, invalid: true},
+		{name: "invalid list", data: map[string]string{"requestheader-username-headers": "X-Remote-User"}, invalid: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			configMap := newAuthConfigMap(t, test.data)
+			if test.noCA {
+				delete(configMap.Data, "requestheader-client-ca-file")
+			}
+			auth, err := ParseRequestHeaderAuth(configMap)
+			if (err != nil) != test.invalid {
+				t.Fatalf("expected invalid %v, got %v", test.invalid, err)
+			}
+			if err == nil && !reflect.DeepEqual(auth.AllowedNames, []string{"front-proxy-client"}) {
+				t.Errorf("expected the allowed names, got %v", auth.AllowedNames)
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	auth := &RequestHeaderAuth{
+		AllowedNames:        []string{"front-proxy-client"},
+		UsernameHeaders:     []string{"X-Remote-User"},
+		GroupHeaders:        []string{"X-Remote-Group"},
+		ExtraHeaderPrefixes: []string{"X-Remote-Extra-"},
+	}
+	headers := map[string][]string{
+		"X-Remote-User":         {"alice"},
+		"X-Remote-Group":        {"dev", "system:authenticated"},
+		"X-Remote-Extra-Scopes": {"metrics"},
+	}
+
+	tests := []struct {
+		name     string
+		req      *http.Request
+		expected *User
+	}{
+		{
+			name: "proxied request",
+			req:  newProxiedRequest("/apis", "front-proxy-client", headers),
+			expected: &User{
+				Name:   "alice",
+				Groups: []string{"dev", "system:authenticated"},
+				Extra:  map[string][]string{"scopes": {"metrics"}},
+			},
+		},
+		{name: "no client certificate", req: httptest.NewRequest(http.MethodGet, "/apis", nil)},
+		{name: "certificate of another name", req: newProxiedRequest("/apis", "admin", headers)},
+		{name: "no user header", req: newProxiedRequest("/apis", "front-proxy-client", nil)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user, ok := auth.Authenticate(test.req)
+			if ok != (test.expected != nil) {
+				t.Fatalf("expected authenticated %v, got %v", test.expected != nil, ok)
+			}
+			if ok && !reflect.DeepEqual(user, test.expected) {
+				t.Errorf("expected %+v, got %+v", test.expected, user)
+			}
+		})
+	}
+}
+
+func TestAccessReview(t *testing.T) {
+	user := &User{Name: "alice", Groups: []string{"dev"}}
+	attributes := func(verb, resource, namespace, name string) *authorizationv1.ResourceAttributes {
+		return &authorizationv1.ResourceAttributes{
+			Group:     GroupVersion.Group,
+			Version:   GroupVersion.Version,
+			Verb:      verb,
+			Resource:  resource,
+			Namespace: namespace,
+			Name:      name,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		parts    []string
+		expected *authorizationv1.ResourceAttributes
+	}{
+		{name: "discovery"},
+		{name: "list nodes", parts: []string{"nodes"}, expected: attributes("list", "nodes", "", "")},
+		{name: "get node", parts: []string{"nodes", "fake-0"}, expected: attributes("get", "nodes", "", "fake-0")},
+		{name: "list all pods", parts: []string{"pods"}, expected: attributes("list", "pods", "", "")},
+		{name: "list pods", parts: []string{"namespaces", "default", "pods"}, expected: attributes("list", "pods", "default", "")},
+		{name: "get pod", parts: []string{"namespaces", "default", "pods", "web"}, expected: attributes("get", "pods", "default", "web")},
+		{name: "unknown resource", parts: []string{"services"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			review := AccessReview(user, test.parts)
+			if test.expected == nil {
+				if review != nil {
+					t.Errorf("expected no review, got %+v", review)
+				}
+				return
+			}
+			if review.Spec.User != "alice" || !reflect.DeepEqual(review.Spec.Groups, user.Groups) {
+				t.Errorf("expected the user, got %+v", review.Spec)
+			}
+			if !reflect.DeepEqual(review.Spec.ResourceAttributes, test.expected) {
+				t.Errorf("expected %+v, got %+v", test.expected, review.Spec.ResourceAttributes)
+			}
+		})
+	}
+}
+
+func TestServeHTTPAuth(t *testing.T) {
+	s := &Server{Client: fake.NewFakeClientWithScheme(clientgoscheme.Scheme, newAuthConfigMap(t, map[string]string{
+		"requestheader-username-headers": `["X-Remote-User"]`,
+	}))}
+	user := map[string][]string{"X-Remote-User": {"alice"}}
+
+	tests := []struct {
+		name     string
+		req      *http.Request
+		expected int
+	}{
+		{name: "health check", req: httptest.NewRequest(http.MethodGet, "/healthz", nil), expected: http.StatusOK},
+		{name: "not proxied", req: httptest.NewRequest(http.MethodGet, "/apis", nil), expected: http.StatusUnauthorized},
+		{name: "discovery", req: newProxiedRequest("/apis", "front-proxy-client", user), expected: http.StatusOK},
+		// The fake client leaves the review not allowed
+		{name: "not allowed", req: newProxiedRequest("/apis/metrics.k8s.io/v1beta1/nodes", "front-proxy-client", user), expected: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.ServeHTTP(w, test.req)
+			if w.Code != test.expected {
+				t.Errorf("expected status %d, got %d: %v", test.expected, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,294 @@
+package metricsapi
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/usage"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Server serves the metrics.k8s.io API for the fake nodes and the managed pods, so
+// that kubectl top, the HPA and the VPA work against them. It is registered to the
+// aggregation layer with an APIService, in place of the metrics-server.
+//
+// Like other aggregated API servers it only serves the requests proxied by the API
+// server, authenticated with the front-proxy client CA, and asks the API server
+// whether their user may read the metrics with a SubjectAccessReview.
+type Server struct {
+	Client client.Client
+	Usage  usage.Provider
+	// Addr is the address the HTTPS server listens on.
+	Addr string
+	// CertDir holds tls.crt and tls.key, a self-signed certificate is used when empty.
+	CertDir string
+	// Window is the reported measurement window.
+	Window time.Duration
+}
+
+// Start runs the server until stop is closed, it implements manager.Runnable.
+func (s *Server) Start(stop <-chan struct{}) error {
+	certificate, err := s.certificate()
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:      s.Addr,
+		Handler:   s,
+		TLSConfig: s.tlsConfig(certificate),
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		klog.Infof("Starting Metrics API Server on %v", s.Addr)
+		errChan <- server.ListenAndServeTLS("", "")
+	}()
+
+	select {
+	case <-stop:
+		return server.Shutdown(context.Background())
+	case err := <-errChan:
+		return err
+	}
+}
+
+func (s *Server) certificate() (tls.Certificate, error) {
+	if s.CertDir != "" {
+		return tls.LoadX509KeyPair(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+	}
+	certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey("node-simulator", nil, nil)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.Trim(req.URL.Path, "/")
+	prefix := "apis/" + GroupVersion.String()
+	if path == "healthz" || path == "livez" || path == "readyz" {
+		_, _ = w.Write([]byte("ok"))
+		return
+	}
+	var parts []string
+	if strings.HasPrefix(path, prefix+"/") {
+		parts = strings.Split(strings.TrimPrefix(path, prefix+"/"), "/")
+	}
+	if !s.authorize(w, req, parts) {
+		return
+	}
+
+	switch {
+	case path == "apis":
+		s.writeJSON(w, &metav1.APIGroupList{
+			TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"},
+			Groups:   []metav1.APIGroup{apiGroup()},
+		})
+		return
+	case path == "apis/"+GroupVersion.Group:
+		group := apiGroup()
+		s.writeJSON(w, &group)
+		return
+	case path == prefix:
+		s.writeJSON(w, apiResourceList())
+		return
+	case !strings.HasPrefix(path, prefix+"/"):
+		http.NotFound(w, req)
+		return
+	}
+
+	selector, err := labels.Parse(req.URL.Query().Get("labelSelector"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && parts[0] == "nodes":
+		s.serveNodes(w, req, "", selector)
+	case len(parts) == 2 && parts[0] == "nodes":
+		s.serveNodes(w, req, parts[1], selector)
+	case len(parts) == 1 && parts[0] == "pods":
+		s.servePods(w, req, "", "", selector)
+	case len(parts) == 3 && parts[0] == "namespaces" && parts[2] == "pods":
+		s.servePods(w, req, parts[1], "", selector)
+	case len(parts) == 4 && parts[0] == "namespaces" && parts[2] == "pods":
+		s.servePods(w, req, parts[1], parts[3], selector)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (s *Server) serveNodes(w http.ResponseWriter, req *http.Request, name string, selector labels.Selector) {
+	ctx := req.Context()
+	now := time.Now()
+
+	nodeList := &v1.NodeList{}
+	if err := s.Client.List(ctx, nodeList, client.MatchingLabels{node.ManageLabelKey: node.ManageLabelValue}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	pods, err := s.runningPods(ctx, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	nodeUsage := make(map[string]v1.ResourceList)
+	for i := range pods {
+		total, ok := nodeUsage[pods[i].Spec.NodeName]
+		if !ok {
+			total = v1.ResourceList{}
+			nodeUsage[pods[i].Spec.NodeName] = total
+		}
+		usage.AddResourceList(total, s.Usage.PodUsage(ctx, &pods[i], now))
+	}
+
+	list := &NodeMetricsList{
+		TypeMeta: metav1.TypeMeta{Kind: "NodeMetricsList", APIVersion: GroupVersion.String()},
+		Items:    make([]NodeMetrics, 0),
+	}
+	for _, fakeNode := range nodeList.Items {
+		if (name != "" && fakeNode.GetName() != name) || !selector.Matches(labels.Set(fakeNode.GetLabels())) {
+			continue
+		}
+		total := nodeUsage[fakeNode.GetName()]
+		if total == nil {
+			total = v1.ResourceList{}
+		}
+		list.Items = append(list.Items, NodeMetrics{
+			TypeMeta: metav1.TypeMeta{Kind: "NodeMetrics", APIVersion: GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              fakeNode.GetName(),
+				Labels:            fakeNode.GetLabels(),
+				CreationTimestamp: metav1.Time{Time: now},
+			},
+			Timestamp: metav1.Time{Time: now},
+			Window:    metav1.Duration{Duration: s.Window},
+			Usage:     total,
+		})
+	}
+
+	if name != "" {
+		if len(list.Items) == 0 {
+			http.Error(w, "nodemetrics \""+name+"\" not found", http.StatusNotFound)
+			return
+		}
+		s.writeJSON(w, &list.Items[0])
+		return
+	}
+	s.writeJSON(w, list)
+}
+
+func (s *Server) servePods(w http.ResponseWriter, req *http.Request, namespace, name string, selector labels.Selector) {
+	ctx := req.Context()
+	now := time.Now()
+	pods, err := s.runningPods(ctx, namespace)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	list := &PodMetricsList{
+		TypeMeta: metav1.TypeMeta{Kind: "PodMetricsList", APIVersion: GroupVersion.String()},
+		Items:    make([]PodMetrics, 0),
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if (name != "" && pod.GetName() != name) || !selector.Matches(labels.Set(pod.GetLabels())) {
+			continue
+		}
+		containers := make([]ContainerMetrics, 0, len(pod.Spec.Containers))
+		for j := range pod.Spec.Containers {
+			containers = append(containers, ContainerMetrics{
+				Name:  pod.Spec.Containers[j].Name,
+				Usage: s.Usage.ContainerUsage(ctx, pod, &pod.Spec.Containers[j], now),
+			})
+		}
+		list.Items = append(list.Items, PodMetrics{
+			TypeMeta: metav1.TypeMeta{Kind: "PodMetrics", APIVersion: GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              pod.GetName(),
+				Namespace:         pod.GetNamespace(),
+				Labels:            pod.GetLabels(),
+				CreationTimestamp: metav1.Time{Time: now},
+			},
+			Timestamp:  metav1.Time{Time: now},
+			Window:     metav1.Duration{Duration: s.Window},
+			Containers: containers,
+		})
+	}
+
+	if name != "" {
+		if len(list.Items) == 0 {
+			http.Error(w, "podmetrics \""+namespace+"/"+name+"\" not found", http.StatusNotFound)
+			return
+		}
+		s.writeJSON(w, &list.Items[0])
+		return
+	}
+	s.writeJSON(w, list)
+}
+
+// runningPods lists the running managed pods bound to a node.
+func (s *Server) runningPods(ctx context.Context, namespace string) ([]v1.Pod, error) {
+	podList := &v1.PodList{}
+	err := s.Client.List(ctx, podList, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: node.ManagedPodSelector})
+	if err != nil {
+		return nil, err
+	}
+	pods := make([]v1.Pod, 0, len(podList.Items))
+	for _, pod := range podList.Items {
+		if pod.Spec.NodeName != "" && pod.Status.Phase == v1.PodRunning {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		klog.Errorf("Metrics API Write Response Error: %v", err)
+	}
+}
+
+func apiGroup() metav1.APIGroup {
+	version := metav1.GroupVersionForDiscovery{
+		GroupVersion: GroupVersion.String(),
+		Version:      GroupVersion.Version,
+	}
+	return metav1.APIGroup{
+		TypeMeta:         metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+		Name:             GroupVersion.Group,
+		Versions:         []metav1.GroupVersionForDiscovery{version},
+		PreferredVersion: version,
+	}
+}
+
+func apiResourceList() *metav1.APIResourceList {
+	return &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: GroupVersion.String(),
+		APIResources: []metav1.APIResource{
+			{Name: "nodes", Kind: "NodeMetrics", Verbs: []string{"get", "list"}},
+			{Name: "pods", Kind: "PodMetrics", Namespaced: true, Verbs: []string{"get", "list"}},
+		},
+	}
+}
@@ -0,0 +1,18 @@
+package replay
+
+import "time"
+
+const (
+	// RunLabelKey holds the name prefix of the replay that created a pod.
+	RunLabelKey = "sim.k8s.io/replay"
+
+	// DefaultNamePrefix names the pods of a replay, followed by the index of their task.
+	DefaultNamePrefix = "replay"
+	// DefaultGPUResource is the resource the GPUs of the tasks are requested as.
+	DefaultGPUResource = "nvidia.com/gpu"
+	// ContainerImage is the image of the replayed pods, which is never pulled.
+	ContainerImage = "sim.k8s.io/replay"
+
+	// ReportPeriod is how often the report is written while the pods run.
+	ReportPeriod = 10 * time.Second
+)
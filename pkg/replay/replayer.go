@@ -0,0 +1,243 @@
+package replay
+
+import (
+	"context"
+	"encoding/csv"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/pod"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups="",resources=pods,verbs=create;list;watch
+
+// Replayer creates a managed pod per task of a trace at its submit time, run by the
+// fake nodes until its recorded duration has passed, and reports when the pods were
+// created, scheduled, started and finished.
+type Replayer struct {
+	Client client.Client
+	Tasks  []Task
+	// Labels of the pods besides RunLabelKey, which select them as managed pods.
+	Labels map[string]string
+	// Namespace of the pods.
+	Namespace string
+	// NamePrefix of the pods, DefaultNamePrefix when empty.
+	NamePrefix string
+	// SpeedUp divides the submit times and durations of the tasks, 1 when not positive.
+	SpeedUp float64
+	// SchedulerName of the pods, the default scheduler when empty.
+	SchedulerName string
+	// GPUResource is the resource the GPUs are requested as, DefaultGPUResource when empty.
+	GPUResource v1.ResourceName
+	// ReportPath is the CSV file the report is written to, none when empty.
+	ReportPath string
+
+	created map[string]time.Time
+}
+
+// Start replays the tasks until they are all finished or stop is closed, it
+// implements manager.Runnable.
+func (r *Replayer) Start(stop <-chan struct{}) error {
+	if r.NamePrefix == "" {
+		r.NamePrefix = DefaultNamePrefix
+	}
+	if r.SpeedUp <= 0 {
+		r.SpeedUp = 1
+	}
+	if r.GPUResource == "" {
+		r.GPUResource = DefaultGPUResource
+	}
+	r.created = make(map[string]time.Time, len(r.Tasks))
+
+	forever := 0
+	for _, task := range r.Tasks {
+		if task.Duration <= 0 {
+			forever++
+		}
+	}
+	klog.Infof("Replaying %d tasks in namespace %v at %vx, %d of them without a duration run forever",
+		len(r.Tasks), r.Namespace, r.SpeedUp, forever)
+	start := time.Now()
+	ticker := time.NewTicker(ReportPeriod)
+	defer ticker.Stop()
+	for i := range r.Tasks {
+		timer := time.NewTimer(time.Until(start.Add(r.scale(r.Tasks[i].Submit))))
+	wait:
+		for {
+			select {
+			case <-stop:
+				timer.Stop()
+				r.report()
+				return nil
+			case <-ticker.C:
+				r.report()
+			case <-timer.C:
+				break wait
+			}
+		}
+		r.createPod(i)
+	}
+	klog.Infof("Replayed %d tasks in %v", len(r.Tasks), time.Since(start))
+
+	for !r.report() {
+		select {
+		case <-stop:
+			r.report()
+			return nil
+		case <-ticker.C:
+		}
+	}
+	klog.Infof("All the replayed pods with a duration finished, out of %d", len(r.Tasks))
+	return nil
+}
+
+func (r *Replayer) scale(d time.Duration) time.Duration {
+	return time.Duration(float64(d) / r.SpeedUp)
+}
+
+func (r *Replayer) podName(i int) string {
+	return r.NamePrefix + "-" + strconv.Itoa(i)
+}
+
+func (r *Replayer) createPod(i int) {
+	task := r.Tasks[i]
+	resources := v1.ResourceList{}
+	if task.CPU > 0 {
+		resources[v1.ResourceCPU] = *resource.NewMilliQuantity(int64(math.Ceil(task.CPU*1000)), resource.DecimalSI)
+	}
+	if task.Memory > 0 {
+		resources[v1.ResourceMemory] = *resource.NewQuantity(task.Memory, resource.BinarySI)
+	}
+	if task.GPU > 0 {
+		resources[r.GPUResource] = *resource.NewQuantity(int64(math.Ceil(task.GPU)), resource.DecimalSI)
+	}
+
+	annotations := map[string]string{}
+	if task.Duration > 0 {
+		annotations[pod.RunAnnotationKey] = strconv.FormatFloat(r.scale(task.Duration).Seconds(), 'f', -1, 64)
+	}
+	podLabels := map[string]string{RunLabelKey: r.NamePrefix}
+	for key, value := range r.Labels {
+		podLabels[key] = value
+	}
+	newPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        r.podName(i),
+			Namespace:   r.Namespace,
+			Labels:      podLabels,
+			Annotations: annotations,
+		},
+		Spec: v1.PodSpec{
+			SchedulerName: r.SchedulerName,
+			NodeSelector:  map[string]string{node.ManageLabelKey: node.ManageLabelValue},
+			RestartPolicy: v1.RestartPolicyNever,
+			Containers: []v1.Container{
+				{
+					Name:  "task",
+					Image: ContainerImage,
+					Resources: v1.ResourceRequirements{
+						Requests: resources,
+						Limits:   resources,
+					},
+				},
+			},
+		},
+	}
+
+	err := r.Client.Create(context.TODO(), newPod)
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		klog.Errorf("Replay: %v Create Pod %v/%v Error: %v", task.Name, r.Namespace, newPod.GetName(), err)
+		return
+	}
+	r.created[newPod.GetName()] = time.Now()
+}
+
+// report writes the report of the created pods and returns whether they are all
+// finished, but for the ones of the tasks without a duration, which run forever.
+func (r *Replayer) report() bool {
+	podList := &v1.PodList{}
+	if err := r.Client.List(context.TODO(), podList, client.InNamespace(r.Namespace),
+		client.MatchingLabels{RunLabelKey: r.NamePrefix}); err != nil {
+		klog.Errorf("Replay: List Pods Error: %v", err)
+		return false
+	}
+	pods := make(map[string]*v1.Pod, len(podList.Items))
+	for i := range podList.Items {
+		pods[podList.Items[i].GetName()] = &podList.Items[i]
+	}
+
+	finished := true
+	rows := [][]string{{"task", "pod", "node", "submit", "created", "scheduled", "started", "finished", "phase"}}
+	for i, task := range r.Tasks {
+		name := r.podName(i)
+		created, ok := r.created[name]
+		if !ok {
+			continue
+		}
+		row := []string{task.Name, name, "", strconv.FormatFloat(r.scale(task.Submit).Seconds(), 'f', -1, 64),
+			created.Format(time.RFC3339Nano), "", "", "", ""}
+		p, ok := pods[name]
+		if !ok {
+			// Deleted
+			rows = append(rows, row)
+			continue
+		}
+		row[2] = p.Spec.NodeName
+		for _, condition := range p.Status.Conditions {
+			if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionTrue {
+				row[5] = condition.LastTransitionTime.Format(time.RFC3339Nano)
+			}
+		}
+		if p.Status.StartTime != nil {
+			row[6] = p.Status.StartTime.Format(time.RFC3339Nano)
+		}
+		if t := finishTime(p); !t.IsZero() {
+			row[7] = t.Format(time.RFC3339Nano)
+		}
+		row[8] = string(p.Status.Phase)
+		if task.Duration > 0 && p.Status.Phase != v1.PodSucceeded && p.Status.Phase != v1.PodFailed {
+			finished = false
+		}
+		rows = append(rows, row)
+	}
+
+	if r.ReportPath != "" {
+		if err := writeCSV(r.ReportPath, rows); err != nil {
+			klog.Errorf("Replay: Write Report %v Error: %v", r.ReportPath, err)
+		}
+	}
+	return finished
+}
+
+// finishTime returns when the last container of the pod terminated.
+func finishTime(p *v1.Pod) time.Time {
+	var finished time.Time
+	for _, status := range p.Status.ContainerStatuses {
+		if status.State.Terminated != nil && status.State.Terminated.FinishedAt.After(finished) {
+			finished = status.State.Terminated.FinishedAt.Time
+		}
+	}
+	return finished
+}
+
+func writeCSV(path string, rows [][]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(file)
+	if err := writer.WriteAll(rows); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,273 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Task is a pod of a trace.
+type Task struct {
+	// Name of the task in the trace, the row number when the trace has none.
+	Name string
+	// Submit is the time the task is submitted at, relative to the first task.
+	Submit time.Duration
+	// Duration is how long the task runs, forever when 0.
+	Duration time.Duration
+	// CPU is the number of cores requested.
+	CPU float64
+	// Memory is the number of bytes requested.
+	Memory int64
+	// GPU is the number of GPUs requested.
+	GPU float64
+}
+
+// Format maps the columns of a trace to the fields of the tasks.
+type Format struct {
+	// Columns names the columns of traces without a header row.
+	Columns []string
+	// Name, Submit, CPU, Memory and GPU are the columns of the fields, Duration or
+	// else End minus Submit gives the duration.
+	Name string
+	// Instances is the column of the number of pods of a task, one when empty.
+	Instances string
+	Submit    string
+	End       string
+	Duration  string
+	CPU       string
+	Memory    string
+	GPU       string
+	// TimeUnit of Submit, End and Duration.
+	TimeUnit time.Duration
+	// CPUScale is the number of cores of a unit of CPU.
+	CPUScale float64
+	// MemoryUnit is the number of bytes of a unit of Memory.
+	MemoryUnit float64
+	// GPUScale is the number of GPUs of a unit of GPU.
+	GPUScale float64
+}
+
+// Formats are the trace formats known by name.
+var Formats = map[string]Format{
+	// name,submit,duration,cpu,memory,gpu in seconds, cores, MiB and GPUs.
+	"generic": {
+		Name: "name", Submit: "submit", Duration: "duration", CPU: "cpu", Memory: "memory", GPU: "gpu",
+		TimeUnit: time.Second, CPUScale: 1, MemoryUnit: 1 << 20, GPUScale: 1,
+	},
+	// pai_task_table of the Alibaba cluster-trace-gpu-v2020, without a header row. CPU
+	// and GPU are in percent and memory in GB, per instance.
+	"alibaba": {
+		Columns: []string{"job_name", "task_name", "inst_num", "status", "start_time", "end_time", "plan_cpu",
+			"plan_mem", "plan_gpu", "gpu_type"},
+		Name: "task_name", Instances: "inst_num", Submit: "start_time", End: "end_time", CPU: "plan_cpu",
+		Memory: "plan_mem", GPU: "plan_gpu",
+		TimeUnit: time.Second, CPUScale: 0.01, MemoryUnit: 1 << 30, GPUScale: 0.01,
+	},
+	// Instances of the Google Borg cluster-data-2019 exported to CSV, times in
+	// microseconds. The resources, normalized to the largest machine, are read as
+	// cores and GiB.
+	"borg": {
+		Name: "instance", Submit: "start_time", End: "end_time", CPU: "cpus", Memory: "memory",
+		TimeUnit: time.Microsecond, CPUScale: 1, MemoryUnit: 1 << 30, GPUScale: 1,
+	},
+	// vmtable of the Azure public dataset, without a header row. The core count and
+	// memory buckets, such as ">24", are read as their lower bound in cores and GB.
+	"azure": {
+		Columns: []string{"vmid", "subscriptionid", "deploymentid", "vmcreated", "vmdeleted", "maxcpu", "avgcpu",
+			"p95maxcpu", "vmcategory", "vmcorecountbucket", "vmmemorybucket"},
+		Name: "vmid", Submit: "vmcreated", End: "vmdeleted", CPU: "vmcorecountbucket", Memory: "vmmemorybucket",
+		TimeUnit: time.Second, CPUScale: 1, MemoryUnit: 1 << 30, GPUScale: 1,
+	},
+}
+
+// ReadTrace reads the tasks of a CSV trace, or of a JSON trace when path ends with
+// .json: an array of objects, or an object per line, keyed by the columns of the
+// format. A record with several instances makes as many tasks, named after the
+// record and the index of the instance. The tasks are sorted by submit time, which
+// starts at 0.
+func ReadTrace(path string, format Format) ([]Task, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var records []map[string]string
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		records, err = jsonRecords(data)
+	} else {
+		records, err = csvRecords(data, format.Columns)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("trace %v: %v", path, err)
+	}
+
+	tasks := make([]Task, 0, len(records))
+	for i, record := range records {
+		task, err := format.task(record)
+		if err != nil {
+			return nil, fmt.Errorf("trace %v: record %d: %v", path, i+1, err)
+		}
+		if task.Name == "" {
+			task.Name = strconv.Itoa(i)
+		}
+		instances, err := format.number(record, format.Instances)
+		if err != nil {
+			return nil, fmt.Errorf("trace %v: record %d: %v", path, i+1, err)
+		}
+		if instances <= 1 {
+			tasks = append(tasks, task)
+			continue
+		}
+		for j := 0; j < int(instances); j++ {
+			instance := task
+			instance.Name = task.Name + "-" + strconv.Itoa(j)
+			tasks = append(tasks, instance)
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].Submit < tasks[j].Submit })
+	if len(tasks) > 0 {
+		first := tasks[0].Submit
+		for i := range tasks {
+			tasks[i].Submit -= first
+		}
+	}
+	return tasks, nil
+}
+
+func csvRecords(data []byte, columns []string) ([]map[string]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		if len(rows) == 0 {
+			return nil, nil
+		}
+		columns = rows[0]
+		rows = rows[1:]
+	}
+	records := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		record := make(map[string]string, len(columns))
+		for i, column := range columns {
+			if i < len(row) {
+				record[strings.TrimSpace(column)] = strings.TrimSpace(row[i])
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func jsonRecords(data []byte) ([]map[string]string, error) {
+	objects := make([]map[string]interface{}, 0)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &objects); err != nil {
+			return nil, err
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for {
+			object := make(map[string]interface{})
+			if err := decoder.Decode(&object); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			objects = append(objects, object)
+		}
+	}
+
+	records := make([]map[string]string, 0, len(objects))
+	for _, object := range objects {
+		record := make(map[string]string, len(object))
+		for key, value := range object {
+			switch v := value.(type) {
+			case nil:
+			case string:
+				record[key] = v
+			case float64:
+				record[key] = strconv.FormatFloat(v, 'f', -1, 64)
+			default:
+				record[key] = fmt.Sprint(v)
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (f Format) task(record map[string]string) (Task, error) {
+	task := Task{Name: record[f.Name]}
+	submit, err := f.number(record, f.Submit)
+	if err != nil {
+		return task, err
+	}
+	task.Submit = f.duration(submit)
+
+	switch {
+	case f.Duration != "":
+		duration, err := f.number(record, f.Duration)
+		if err != nil {
+			return task, err
+		}
+		task.Duration = f.duration(duration)
+	case f.End != "" && record[f.End] != "":
+		end, err := f.number(record, f.End)
+		if err != nil {
+			return task, err
+		}
+		if end > submit {
+			task.Duration = f.duration(end - submit)
+		}
+	}
+
+	cpu, err := f.number(record, f.CPU)
+	if err != nil {
+		return task, err
+	}
+	task.CPU = cpu * f.CPUScale
+	memory, err := f.number(record, f.Memory)
+	if err != nil {
+		return task, err
+	}
+	task.Memory = int64(math.Round(memory * f.MemoryUnit))
+	gpu, err := f.number(record, f.GPU)
+	if err != nil {
+		return task, err
+	}
+	task.GPU = gpu * f.GPUScale
+	return task, nil
+}
+
+// number parses the column of the record, 0 when the format or the record has no
+// such column. Buckets such as ">24" are read as their bound.
+func (f Format) number(record map[string]string, column string) (float64, error) {
+	if column == "" {
+		return 0, nil
+	}
+	value := strings.TrimLeft(record[column], "<>=")
+	if value == "" {
+		return 0, nil
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("column %v: %v", column, err)
+	}
+	return number, nil
+}
+
+func (f Format) duration(value float64) time.Duration {
+	return time.Duration(value * float64(f.TimeUnit))
+}
@@ -0,0 +1,124 @@
+package replay
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReadTrace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "trace")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name     string
+		format   string
+		file     string
+		data     string
+		expected []Task
+		wantErr  bool
+	}{
+		{
+			name:   "generic csv sorted by submit time",
+			format: "generic",
+			file:   "generic.csv",
+			data: `name,submit,duration,cpu,memory,gpu
+b,130,0,2,512,1
+a,100,60,0.5,1024,
+`,
+			expected: []Task{
+				{Name: "a", Duration: time.Minute, CPU: 0.5, Memory: 1 << 30},
+				{Name: "b", Submit: 30 * time.Second, CPU: 2, Memory: 512 << 20, GPU: 1},
+			},
+		},
+		{
+			name:   "generic json array without names",
+			format: "generic",
+			file:   "array.json",
+			data:   `[{"submit": 5, "duration": 10, "cpu": 1, "memory": 256}, {"submit": 7, "cpu": "2"}]`,
+			expected: []Task{
+				{Name: "0", Duration: 10 * time.Second, CPU: 1, Memory: 256 << 20},
+				{Name: "1", Submit: 2 * time.Second, CPU: 2},
+			},
+		},
+		{
+			name:   "generic json lines",
+			format: "generic",
+			file:   "lines.json",
+			data: `{"name": "x", "submit": 1, "duration": 2, "cpu": 1}
+{"name": "y", "submit": 3, "duration": null, "cpu": 1}
+`,
+			expected: []Task{
+				{Name: "x", Duration: 2 * time.Second, CPU: 1},
+				{Name: "y", Submit: 2 * time.Second, CPU: 1},
+			},
+		},
+		{
+			name:   "alibaba instances",
+			format: "alibaba",
+			file:   "pai_task_table.csv",
+			data: `j1,worker,2,Terminated,1000,1100,600,29.296875,50,V100
+j2,ps,1,Running,1010,,100,2,,
+`,
+			expected: []Task{
+				{Name: "worker-0", Duration: 100 * time.Second, CPU: 6, Memory: 31457280000, GPU: 0.5},
+				{Name: "worker-1", Duration: 100 * time.Second, CPU: 6, Memory: 31457280000, GPU: 0.5},
+				{Name: "ps", Submit: 10 * time.Second, CPU: 1, Memory: 2 << 30},
+			},
+		},
+		{
+			name:   "borg",
+			format: "borg",
+			file:   "borg.csv",
+			data: `instance,start_time,end_time,cpus,memory
+i1,2000000,5000000,0.25,0.5
+`,
+			expected: []Task{
+				{Name: "i1", Duration: 3 * time.Second, CPU: 0.25, Memory: 1 << 29},
+			},
+		},
+		{
+			name:   "azure buckets",
+			format: "azure",
+			file:   "vmtable.csv",
+			data: `vm1,s,d,0,3600,90,10,80,Delay-insensitive,>24,>64
+vm2,s,d,300,600,50,5,40,Interactive,2,4
+`,
+			expected: []Task{
+				{Name: "vm1", Duration: time.Hour, CPU: 24, Memory: 64 << 30},
+				{Name: "vm2", Submit: 5 * time.Minute, Duration: 5 * time.Minute, CPU: 2, Memory: 4 << 30},
+			},
+		},
+		{
+			name:   "invalid number",
+			format: "generic",
+			file:   "invalid.csv",
+			data: `name,submit,cpu
+a,soon,1
+`,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, test.file)
+			if err := ioutil.WriteFile(path, []byte(test.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			tasks, err := ReadTrace(path, Formats[test.format])
+			if (err != nil) != test.wantErr {
+				t.Fatalf("expected error %v, got %v", test.wantErr, err)
+			}
+			if !test.wantErr && !reflect.DeepEqual(tasks, test.expected) {
+				t.Errorf("expected %+v, got %+v", test.expected, tasks)
+			}
+		})
+	}
+}
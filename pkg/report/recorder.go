@@ -0,0 +1,168 @@
+package report
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/metrics"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Placement is the outcome of a managed pod bound to a fake node.
+type Placement struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Node      string `json:"node"`
+	// Outcome is metrics.OutcomeRunning or metrics.OutcomeRejected.
+	Outcome   string    `json:"outcome"`
+	Created   time.Time `json:"created"`
+	Scheduled time.Time `json:"scheduled"`
+	// Running is when the pod started, zero when it was rejected.
+	Running time.Time `json:"running"`
+}
+
+// SchedulingLatency is the time from the creation of the pod to its binding.
+func (p *Placement) SchedulingLatency() time.Duration {
+	return p.Scheduled.Sub(p.Created)
+}
+
+// StartupLatency is the time from the binding of the pod to its start.
+func (p *Placement) StartupLatency() time.Duration {
+	return p.Running.Sub(p.Scheduled)
+}
+
+// Recorder records the placements of the managed pods run by this replica, and
+// observes their latencies in the Prometheus histograms. A nil Recorder only
+// observes the histograms.
+type Recorder struct {
+	mu         sync.Mutex
+	placements map[types.UID]Placement
+	observed   map[types.UID]observation
+}
+
+// observation holds the watch event times of a pod seen before it was bound.
+type observation struct {
+	created   time.Time
+	scheduled time.Time
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{
+		placements: make(map[types.UID]Placement),
+		observed:   make(map[types.UID]observation),
+	}
+}
+
+// Observe records the time of a watch event of the pod. The creationTimestamp and
+// the PodScheduled condition have a second resolution, so a pod first seen unbound
+// is placed with the time of its first event as its creation, and the time of its
+// first event with spec.nodeName as its binding. Pods first seen bound, such as
+// those created before the replica started, keep the API times.
+func (r *Recorder) Observe(pod *v1.Pod, now time.Time) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.placements[pod.GetUID()]; ok {
+		return
+	}
+	seen, ok := r.observed[pod.GetUID()]
+	switch {
+	case !ok && pod.Spec.NodeName == "":
+		r.observed[pod.GetUID()] = observation{created: now}
+	case ok && pod.Spec.NodeName != "" && seen.scheduled.IsZero():
+		seen.scheduled = now
+		r.observed[pod.GetUID()] = seen
+	}
+}
+
+// Forget drops the watch event times of a deleted pod.
+func (r *Recorder) Forget(pod *v1.Pod) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.observed, pod.GetUID())
+}
+
+// Started records a pod bound at scheduled and started at running.
+func (r *Recorder) Started(pod *v1.Pod, scheduled, running time.Time) {
+	placement := newPlacement(pod, metrics.OutcomeRunning, scheduled)
+	placement.Running = running
+	if r.add(pod, &placement) {
+		metrics.PodSchedulingLatency.Observe(placement.SchedulingLatency().Seconds())
+		metrics.PodStartupLatency.Observe(placement.StartupLatency().Seconds())
+		metrics.PodPlacements.WithLabelValues(metrics.OutcomeRunning).Inc()
+	}
+}
+
+// Rejected records a pod bound at scheduled and rejected at admission.
+func (r *Recorder) Rejected(pod *v1.Pod, scheduled time.Time) {
+	placement := newPlacement(pod, metrics.OutcomeRejected, scheduled)
+	if r.add(pod, &placement) {
+		metrics.PodSchedulingLatency.Observe(placement.SchedulingLatency().Seconds())
+		metrics.PodPlacements.WithLabelValues(metrics.OutcomeRejected).Inc()
+	}
+}
+
+// Placements returns the recorded placements.
+func (r *Recorder) Placements() []Placement {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	placements := make([]Placement, 0, len(r.placements))
+	for _, placement := range r.placements {
+		placements = append(placements, placement)
+	}
+	return placements
+}
+
+// Reset forgets the recorded placements, to start a new run.
+func (r *Recorder) Reset() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.placements = make(map[types.UID]Placement)
+}
+
+// add records the placement of the pod with the observed times, and returns false
+// when the pod already has one.
+func (r *Recorder) add(pod *v1.Pod, placement *Placement) bool {
+	if r == nil {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.placements[pod.GetUID()]; ok {
+		return false
+	}
+	if seen, ok := r.observed[pod.GetUID()]; ok && !seen.scheduled.IsZero() {
+		placement.Created = seen.created
+		placement.Scheduled = seen.scheduled
+		if !placement.Running.IsZero() && placement.Running.Before(seen.scheduled) {
+			placement.Running = seen.scheduled
+		}
+	}
+	delete(r.observed, pod.GetUID())
+	r.placements[pod.GetUID()] = *placement
+	return true
+}
+
+func newPlacement(pod *v1.Pod, outcome string, scheduled time.Time) Placement {
+	return Placement{
+		Namespace: pod.GetNamespace(),
+		Name:      pod.GetName(),
+		Node:      pod.Spec.NodeName,
+		Outcome:   outcome,
+		Created:   pod.GetCreationTimestamp().Time,
+		Scheduled: scheduled,
+	}
+}
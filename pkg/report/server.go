@@ -0,0 +1,105 @@
+package report
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Server serves the scheduling report over HTTP:
+//
+//	GET /report             the Summary as JSON
+//	GET /report/placements  the recorded placements as CSV
+//	DELETE /report          forgets the recorded placements, to start a new run
+type Server struct {
+	Client   client.Client
+	Recorder *Recorder
+	// Addr is the address the HTTP server listens on.
+	Addr string
+}
+
+// Start runs the server until stop is closed, it implements manager.Runnable.
+func (s *Server) Start(stop <-chan struct{}) error {
+	server := &http.Server{
+		Addr:    s.Addr,
+		Handler: s,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		klog.Infof("Starting Report Server on %v", s.Addr)
+		errChan <- server.ListenAndServe()
+	}()
+
+	select {
+	case <-stop:
+		return server.Shutdown(context.Background())
+	case err := <-errChan:
+		return err
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.Trim(req.URL.Path, "/")
+	switch {
+	case path == "healthz":
+		_, _ = w.Write([]byte("ok"))
+	case path == "report" && req.Method == http.MethodGet:
+		summary, err := Summarize(req.Context(), s.Client, s.Recorder)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(summary); err != nil {
+			klog.Errorf("Report Write Response Error: %v", err)
+		}
+	case path == "report" && req.Method == http.MethodDelete:
+		s.Recorder.Reset()
+		w.WriteHeader(http.StatusNoContent)
+	case path == "report/placements" && req.Method == http.MethodGet:
+		s.servePlacements(w)
+	case path == "report" || path == "report/placements":
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func (s *Server) servePlacements(w http.ResponseWriter) {
+	placements := s.Recorder.Placements()
+	sort.Slice(placements, func(i, j int) bool {
+		return placements[i].Created.Before(placements[j].Created)
+	})
+
+	w.Header().Set("Content-Type", "text/csv")
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"namespace", "name", "node", "outcome", "created", "scheduled", "running",
+		"schedulingSeconds", "startupSeconds"})
+	for _, placement := range placements {
+		row := []string{placement.Namespace, placement.Name, placement.Node, placement.Outcome,
+			placement.Created.Format(time.RFC3339Nano), placement.Scheduled.Format(time.RFC3339Nano), "",
+			formatSeconds(placement.SchedulingLatency()), ""}
+		if !placement.Running.IsZero() {
+			row[6] = placement.Running.Format(time.RFC3339Nano)
+			row[8] = formatSeconds(placement.StartupLatency())
+		}
+		_ = writer.Write(row)
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		klog.Errorf("Report Write Response Error: %v", err)
+	}
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
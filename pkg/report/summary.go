@@ -0,0 +1,193 @@
+package report
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/metrics"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/usage"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Summary reports the scheduling latencies, the placement outcomes and the spread of
+// the requests over the fake nodes.
+type Summary struct {
+	Time time.Time `json:"time"`
+	// Pods counts the managed pods by outcome, and the pending ones not bound yet.
+	Pods map[string]int `json:"pods"`
+	// SchedulingLatency is the time from creation to binding, in seconds.
+	SchedulingLatency Percentiles `json:"schedulingLatencySeconds"`
+	// StartupLatency is the time from binding to running, in seconds.
+	StartupLatency Percentiles `json:"startupLatencySeconds"`
+	// Nodes is the number of fake nodes.
+	Nodes int `json:"nodes"`
+	// Resources reports the allocation of each allocatable resource of the fake nodes.
+	Resources map[v1.ResourceName]Allocation `json:"resources"`
+}
+
+// Percentiles of a sample.
+type Percentiles struct {
+	Count int     `json:"count"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+	Max   float64 `json:"max"`
+}
+
+// Allocation of a resource over the fake nodes.
+type Allocation struct {
+	Allocatable float64 `json:"allocatable"`
+	Requested   float64 `json:"requested"`
+	// Utilization is the fraction of the allocatable requested over all the nodes,
+	// and MinUtilization, MaxUtilization and StdDevUtilization its spread per node.
+	Utilization       float64 `json:"utilization"`
+	MinUtilization    float64 `json:"minUtilization"`
+	MaxUtilization    float64 `json:"maxUtilization"`
+	StdDevUtilization float64 `json:"stdDevUtilization"`
+	// Fragmentation is 1 minus the largest free amount of a node over the free amount
+	// of all the nodes: the share of the free resource a single pod cannot get.
+	Fragmentation float64 `json:"fragmentation"`
+}
+
+// Pending is the key of the pods not bound yet in Summary.Pods.
+const Pending = "pending"
+
+// Summarize computes the summary of the recorded placements and of the fake nodes.
+func Summarize(ctx context.Context, c client.Client, recorder *Recorder) (*Summary, error) {
+	summary := &Summary{
+		Time: time.Now(),
+		Pods: map[string]int{
+			metrics.OutcomeRunning:  0,
+			metrics.OutcomeRejected: 0,
+			Pending:                 0,
+		},
+		Resources: make(map[v1.ResourceName]Allocation),
+	}
+
+	scheduling := make([]float64, 0)
+	startup := make([]float64, 0)
+	for _, placement := range recorder.Placements() {
+		summary.Pods[placement.Outcome]++
+		scheduling = append(scheduling, placement.SchedulingLatency().Seconds())
+		if placement.Outcome == metrics.OutcomeRunning {
+			startup = append(startup, placement.StartupLatency().Seconds())
+		}
+	}
+	summary.SchedulingLatency = percentiles(scheduling)
+	summary.StartupLatency = percentiles(startup)
+
+	nodeList := &v1.NodeList{}
+	if err := c.List(ctx, nodeList, client.MatchingLabels{node.ManageLabelKey: node.ManageLabelValue}); err != nil {
+		return nil, err
+	}
+	podList := &v1.PodList{}
+	if err := c.List(ctx, podList); err != nil {
+		return nil, err
+	}
+
+	requested := make(map[string]v1.ResourceList, len(nodeList.Items))
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if pod.Spec.NodeName == "" {
+			if node.IsManagedPod(pod) {
+				summary.Pods[Pending]++
+			}
+			continue
+		}
+		total, ok := requested[pod.Spec.NodeName]
+		if !ok {
+			total = v1.ResourceList{}
+			requested[pod.Spec.NodeName] = total
+		}
+		for _, container := range pod.Spec.Containers {
+			usage.AddResourceList(total, container.Resources.Requests)
+		}
+	}
+
+	summary.Nodes = len(nodeList.Items)
+	utilizations := make(map[v1.ResourceName][]float64)
+	maxFree := make(map[v1.ResourceName]float64)
+	for _, fakeNode := range nodeList.Items {
+		for name, quantity := range fakeNode.Status.Allocatable {
+			if name == v1.ResourcePods {
+				continue
+			}
+			allocatable := quantityValue(quantity)
+			if allocatable <= 0 {
+				continue
+			}
+			used := quantityValue(requested[fakeNode.GetName()][name])
+			allocation := summary.Resources[name]
+			allocation.Allocatable += allocatable
+			allocation.Requested += used
+			summary.Resources[name] = allocation
+			utilizations[name] = append(utilizations[name], used/allocatable)
+			if free := allocatable - used; free > maxFree[name] {
+				maxFree[name] = free
+			}
+		}
+	}
+
+	for name, allocation := range summary.Resources {
+		values := utilizations[name]
+		allocation.Utilization = allocation.Requested / allocation.Allocatable
+		allocation.MinUtilization, allocation.MaxUtilization = values[0], values[0]
+		var sum, squares float64
+		for _, value := range values {
+			allocation.MinUtilization = math.Min(allocation.MinUtilization, value)
+			allocation.MaxUtilization = math.Max(allocation.MaxUtilization, value)
+			sum += value
+			squares += value * value
+		}
+		mean := sum / float64(len(values))
+		allocation.StdDevUtilization = math.Sqrt(math.Max(squares/float64(len(values))-mean*mean, 0))
+		if free := allocation.Allocatable - allocation.Requested; free > 0 {
+			allocation.Fragmentation = 1 - maxFree[name]/free
+		}
+		summary.Resources[name] = allocation
+	}
+	return summary, nil
+}
+
+// quantityValue returns the quantity in its unit, cores for the CPU.
+func quantityValue(quantity resource.Quantity) float64 {
+	if value := quantity.Value(); value > math.MaxInt64/1000 {
+		return float64(value)
+	}
+	return float64(quantity.MilliValue()) / 1000
+}
+
+// percentiles computes the nearest-rank percentiles of values.
+func percentiles(values []float64) Percentiles {
+	result := Percentiles{Count: len(values)}
+	if len(values) == 0 {
+		return result
+	}
+	sort.Float64s(values)
+	rank := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(values)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return values[i]
+	}
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	result.Mean = sum / float64(len(values))
+	result.P50 = rank(0.5)
+	result.P90 = rank(0.9)
+	result.P99 = rank(0.99)
+	result.Max = values[len(values)-1]
+	return result
+}
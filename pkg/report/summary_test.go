@@ -0,0 +1,148 @@
+package report
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	"github.com/NJUPT-ISL/NodeSimulator/pkg/metrics"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPercentiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		expected Percentiles
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:     "single value",
+			values:   []float64{2},
+			expected: Percentiles{Count: 1, Mean: 2, P50: 2, P90: 2, P99: 2, Max: 2},
+		},
+		{
+			name:     "nearest rank of unsorted values",
+			values:   []float64{10, 1, 9, 2, 8, 3, 7, 4, 6, 5},
+			expected: Percentiles{Count: 10, Mean: 5.5, P50: 5, P90: 9, P99: 10, Max: 10},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := percentiles(test.values); !reflect.DeepEqual(result, test.expected) {
+				t.Errorf("expected %+v, got %+v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	managed := map[string]string{node.ManageLabelKey: node.ManageLabelValue}
+	newNode := func(name, cpu string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: managed},
+			Status: v1.NodeStatus{Allocatable: v1.ResourceList{
+				v1.ResourceCPU:  resource.MustParse(cpu),
+				v1.ResourcePods: resource.MustParse("110"),
+			}},
+		}
+	}
+	newPod := func(name, nodeName, cpu string, phase v1.PodPhase) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name), Labels: managed},
+			Spec: v1.PodSpec{
+				NodeName: nodeName,
+				Containers: []v1.Container{{
+					Name:      "c",
+					Resources: v1.ResourceRequirements{Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse(cpu)}},
+				}},
+			},
+			Status: v1.PodStatus{Phase: phase},
+		}
+	}
+	objects := []runtime.Object{
+		newNode("n0", "4"),
+		newNode("n1", "4"),
+		newPod("p0", "n0", "3", v1.PodRunning),
+		newPod("p1", "n1", "1", v1.PodRunning),
+		newPod("p2", "n1", "2", v1.PodFailed),
+		newPod("p3", "", "1", v1.PodPending),
+	}
+	c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, objects...)
+
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder := NewRecorder()
+	for i, name := range []string{"p0", "p1"} {
+		pod := newPod(name, "n0", "1", v1.PodPending)
+		pod.CreationTimestamp = metav1.NewTime(created)
+		scheduled := created.Add(time.Duration(i+1) * time.Second)
+		recorder.Started(pod, scheduled, scheduled.Add(time.Second))
+	}
+	rejected := newPod("p2", "n1", "2", v1.PodPending)
+	rejected.CreationTimestamp = metav1.NewTime(created)
+	recorder.Rejected(rejected, created.Add(3*time.Second))
+
+	summary, err := Summarize(context.TODO(), c, recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		actual   interface{}
+		expected interface{}
+	}{
+		{
+			name:     "pods",
+			actual:   summary.Pods,
+			expected: map[string]int{metrics.OutcomeRunning: 2, metrics.OutcomeRejected: 1, Pending: 1},
+		},
+		{
+			name:     "scheduling latency",
+			actual:   summary.SchedulingLatency,
+			expected: Percentiles{Count: 3, Mean: 2, P50: 2, P90: 3, P99: 3, Max: 3},
+		},
+		{
+			name:     "startup latency",
+			actual:   summary.StartupLatency,
+			expected: Percentiles{Count: 2, Mean: 1, P50: 1, P90: 1, P99: 1, Max: 1},
+		},
+		{
+			name:     "nodes",
+			actual:   summary.Nodes,
+			expected: 2,
+		},
+		{
+			name:   "resources",
+			actual: summary.Resources,
+			expected: map[v1.ResourceName]Allocation{v1.ResourceCPU: {
+				Allocatable:       8,
+				Requested:         4,
+				Utilization:       0.5,
+				MinUtilization:    0.25,
+				MaxUtilization:    0.75,
+				StdDevUtilization: 0.25,
+				Fragmentation:     0.25,
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !reflect.DeepEqual(test.actual, test.expected) {
+				t.Errorf("expected %+v, got %+v", test.expected, test.actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package shard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	cov1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
+
+const (
+	// LabelKey marks the Leases of the simulator replicas.
+	LabelKey   = "sim.k8s.io/shard"
+	LabelValue = "true"
+	// LeasePrefix prefixes the name of the Lease of a replica.
+	LeasePrefix = "nodesimulator-shard-"
+	// VirtualNodes is the number of points of a replica on the hash ring.
+	VirtualNodes = 100
+)
+
+type point struct {
+	hash     uint32
+	identity string
+}
+
+// Sharder splits the fake nodes between the simulator replicas by consistent
+// hashing of the node names. Every replica holds a Lease labeled LabelKey, the
+// replicas with an unexpired Lease make up the ring, and the handlers registered
+// with OnChange are called whenever a replica joins or leaves it. A nil Sharder owns
+// every node.
+type Sharder struct {
+	Client client.Client
+	// Identity of the replica, unique among the replicas.
+	Identity string
+	// Namespace of the Leases.
+	Namespace string
+	// LeaseDuration after which a replica that stopped renewing its Lease leaves the ring.
+	LeaseDuration time.Duration
+	// RenewPeriod is how often the Lease is renewed and the ring rebuilt.
+	RenewPeriod time.Duration
+
+	mu       sync.RWMutex
+	members  []string
+	ring     []point
+	handlers []func()
+}
+
+// Owns reports whether the replica handles the node.
+func (s *Sharder) Owns(nodeName string) bool {
+	if s == nil {
+		return true
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.ring) == 0 {
+		return false
+	}
+	hash := hashOf(nodeName)
+	i := sort.Search(len(s.ring), func(i int) bool { return s.ring[i].hash >= hash })
+	if i == len(s.ring) {
+		i = 0
+	}
+	return s.ring[i].identity == s.Identity
+}
+
+// OnChange registers a handler called after the members of the ring changed.
+func (s *Sharder) OnChange(handler func()) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// Start renews the Lease of the replica and rebuilds the ring until stop is closed,
+// it implements manager.Runnable. The Lease is deleted on the way out so that the
+// other replicas take the nodes over without waiting for it to expire.
+func (s *Sharder) Start(stop <-chan struct{}) error {
+	klog.Infof("Starting Sharder %v in namespace %v", s.Identity, s.Namespace)
+	wait.Until(func() {
+		ctx := context.TODO()
+		if err := s.renew(ctx); err != nil {
+			klog.Errorf("Renew Shard Lease: %v Error: %v", s.Identity, err)
+		}
+		if err := s.sync(ctx); err != nil {
+			klog.Errorf("Sync Shard Ring Error: %v", err)
+		}
+	}, s.RenewPeriod, stop)
+
+	lease := &cov1.Lease{}
+	lease.SetName(LeasePrefix + s.Identity)
+	lease.SetNamespace(s.Namespace)
+	if err := s.Client.Delete(context.TODO(), lease); err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("Delete Shard Lease: %v Error: %v", s.Identity, err)
+	}
+	return nil
+}
+
+func (s *Sharder) renew(ctx context.Context) error {
+	duration := int32(s.LeaseDuration.Seconds())
+	renewTime := metav1.MicroTime{Time: time.Now()}
+	spec := cov1.LeaseSpec{
+		HolderIdentity:       &s.Identity,
+		LeaseDurationSeconds: &duration,
+		RenewTime:            &renewTime,
+	}
+
+	lease := &cov1.Lease{}
+	err := s.Client.Get(ctx, types.NamespacedName{Namespace: s.Namespace, Name: LeasePrefix + s.Identity}, lease)
+	if err != nil && apierrors.IsNotFound(err) {
+		lease = &cov1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      LeasePrefix + s.Identity,
+				Namespace: s.Namespace,
+				Labels:    map[string]string{LabelKey: LabelValue},
+			},
+			Spec: spec,
+		}
+		return s.Client.Create(ctx, lease)
+	} else if err != nil {
+		return err
+	}
+	lease.Spec = spec
+	return s.Client.Update(ctx, lease)
+}
+
+func (s *Sharder) sync(ctx context.Context) error {
+	leases := &cov1.LeaseList{}
+	if err := s.Client.List(ctx, leases, client.InNamespace(s.Namespace), client.MatchingLabels{LabelKey: LabelValue}); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	members := []string{s.Identity}
+	for _, lease := range leases.Items {
+		spec := lease.Spec
+		if spec.HolderIdentity == nil || *spec.HolderIdentity == s.Identity ||
+			spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expiry := spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+		if expiry.After(now) {
+			members = append(members, *spec.HolderIdentity)
+		}
+	}
+	sort.Strings(members)
+
+	s.mu.Lock()
+	if reflect.DeepEqual(members, s.members) {
+		s.mu.Unlock()
+		return nil
+	}
+	s.members = members
+	s.ring = genRing(members)
+	handlers := append([]func(){}, s.handlers...)
+	s.mu.Unlock()
+
+	klog.Infof("Shard ring of %v changed, members: %v", s.Identity, members)
+	for _, handler := range handlers {
+		go handler()
+	}
+	return nil
+}
+
+func genRing(members []string) []point {
+	ring := make([]point, 0, len(members)*VirtualNodes)
+	for _, member := range members {
+		for i := 0; i < VirtualNodes; i++ {
+			ring = append(ring, point{hash: hashOf(member + "#" + strconv.Itoa(i)), identity: member})
+		}
+	}
+	sort.Slice(ring, func(i, j int) bool { return ring[i].hash < ring[j].hash })
+	return ring
+}
+
+func hashOf(key string) uint32 {
+	sum := sha256.Sum256([]byte(key))
+	return binary.BigEndian.Uint32(sum[:4])
+}
@@ -0,0 +1,155 @@
+package shard
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	cov1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newLease(identity string, renewed time.Time) runtime.Object {
+	duration := int32(15)
+	renewTime := metav1.MicroTime{Time: renewed}
+	return &cov1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      LeasePrefix + identity,
+			Labels:    map[string]string{LabelKey: LabelValue},
+		},
+		Spec: cov1.LeaseSpec{
+			HolderIdentity:       &identity,
+			LeaseDurationSeconds: &duration,
+			RenewTime:            &renewTime,
+		},
+	}
+}
+
+// newSharders returns the synced sharders of identities, sharing the leases.
+func newSharders(t *testing.T, leases []runtime.Object, identities ...string) []*Sharder {
+	c := fake.NewFakeClientWithScheme(clientgoscheme.Scheme, leases...)
+	sharders := make([]*Sharder, 0, len(identities))
+	for _, identity := range identities {
+		s := &Sharder{Client: c, Identity: identity, Namespace: "default"}
+		if err := s.sync(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+		sharders = append(sharders, s)
+	}
+	return sharders
+}
+
+func TestOwns(t *testing.T) {
+	now := time.Now()
+	nodes := make([]string, 1000)
+	for i := range nodes {
+		nodes[i] = "default-sim-" + strconv.Itoa(i)
+	}
+
+	tests := []struct {
+		name    string
+		leases  []runtime.Object
+		members []string
+		// owners is the identities expected to own some nodes.
+		owners []string
+	}{
+		{
+			name:    "single replica",
+			members: []string{"a"},
+			owners:  []string{"a"},
+		},
+		{
+			name:    "three replicas",
+			leases:  []runtime.Object{newLease("a", now), newLease("b", now), newLease("c", now)},
+			members: []string{"a", "b", "c"},
+			owners:  []string{"a", "b", "c"},
+		},
+		{
+			name:    "expired lease left out",
+			leases:  []runtime.Object{newLease("a", now), newLease("b", now.Add(-time.Minute))},
+			members: []string{"a"},
+			owners:  []string{"a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sharders := newSharders(t, test.leases, test.members...)
+			owned := make(map[string]int)
+			for _, name := range nodes {
+				owners := 0
+				for _, s := range sharders {
+					if s.Owns(name) {
+						owners++
+						owned[s.Identity]++
+					}
+				}
+				if owners != 1 {
+					t.Fatalf("node %v has %d owners", name, owners)
+				}
+			}
+			for _, identity := range test.owners {
+				// Virtual nodes keep the shares within a factor of two
+				if share := owned[identity]; share < len(nodes)/len(test.owners)/2 {
+					t.Errorf("replica %v owns %d of %d nodes", identity, share, len(nodes))
+				}
+			}
+		})
+	}
+}
+
+func TestOwnsRebalance(t *testing.T) {
+	now := time.Now()
+	before := newSharders(t, []runtime.Object{newLease("a", now), newLease("b", now)}, "a", "b")
+	after := newSharders(t, []runtime.Object{newLease("a", now), newLease("b", now), newLease("c", now)}, "a", "b", "c")
+	owner := func(sharders []*Sharder, name string) string {
+		for _, s := range sharders {
+			if s.Owns(name) {
+				return s.Identity
+			}
+		}
+		return ""
+	}
+
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		name := "default-sim-" + strconv.Itoa(i)
+		from, to := owner(before, name), owner(after, name)
+		if from == to {
+			continue
+		}
+		// Only the nodes taken over by the new replica move
+		if to != "c" {
+			t.Fatalf("node %v moved from %v to %v", name, from, to)
+		}
+		moved++
+	}
+	if moved == 0 || moved > 500 {
+		t.Errorf("expected about a third of the nodes to move, %d of 1000 did", moved)
+	}
+}
+
+func TestOwnsEdgeCases(t *testing.T) {
+	var nilSharder *Sharder
+	tests := []struct {
+		name     string
+		sharder  *Sharder
+		expected bool
+	}{
+		{name: "nil sharder owns every node", sharder: nilSharder, expected: true},
+		{name: "empty ring owns no node", sharder: &Sharder{Identity: "a"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if owns := test.sharder.Owns("default-sim-0"); owns != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, owns)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package snapshot
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	simnode "github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// Options of the generated NodeSimulators.
+type Options struct {
+	// Namespace of the NodeSimulators.
+	Namespace string
+	// NamePrefix of the NodeSimulators, followed by the index of their group.
+	NamePrefix string
+	// Anonymize replaces the keys and values of the labels and taints outside of
+	// the Kubernetes domains by hashes, which keeps the nodes grouped the same way.
+	Anonymize bool
+	// Salt of the hashes, a random one is used when empty so that the hashes of
+	// known values cannot be looked up.
+	Salt string
+}
+
+// nodeLabels are dropped from the groups, they identify a single node.
+var nodeLabels = map[string]bool{
+	v1.LabelHostname: true,
+}
+
+// nodeTaints are dropped from the groups, they are set by the node lifecycle.
+var nodeTaints = map[string]bool{
+	"node.kubernetes.io/not-ready":                   true,
+	"node.kubernetes.io/unreachable":                 true,
+	"node.kubernetes.io/unschedulable":               true,
+	"node.kubernetes.io/memory-pressure":             true,
+	"node.kubernetes.io/disk-pressure":               true,
+	"node.kubernetes.io/pid-pressure":                true,
+	"node.kubernetes.io/network-unavailable":         true,
+	"node.cloudprovider.kubernetes.io/uninitialized": true,
+	"node.cloudprovider.kubernetes.io/shutdown":      true,
+}
+
+// ReadNodes reads the nodes of a YAML or JSON file, such as the output of
+// `kubectl get nodes -o yaml`. Lists, NodeLists and single Nodes are accepted, in
+// one or more documents.
+func ReadNodes(path string) ([]v1.Node, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]v1.Node, 0)
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%v: %v", path, err)
+		}
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+		typeMeta := metav1.TypeMeta{}
+		if err := json.Unmarshal(raw, &typeMeta); err != nil {
+			return nil, fmt.Errorf("%v: %v", path, err)
+		}
+		switch typeMeta.Kind {
+		case "Node":
+			node := v1.Node{}
+			if err := json.Unmarshal(raw, &node); err != nil {
+				return nil, fmt.Errorf("%v: %v", path, err)
+			}
+			nodes = append(nodes, node)
+		case "List", "NodeList":
+			list := v1.NodeList{}
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return nil, fmt.Errorf("%v: %v", path, err)
+			}
+			for _, node := range list.Items {
+				if node.Kind == "" || node.Kind == "Node" {
+					nodes = append(nodes, node)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%v: unexpected kind %q, expected Node, NodeList or List", path, typeMeta.Kind)
+		}
+	}
+	return nodes, nil
+}
+
+// ListNodes lists the nodes of a cluster.
+func ListNodes(clientSet kubernetes.Interface) ([]v1.Node, error) {
+	nodeList, err := clientSet.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return nodeList.Items, nil
+}
+
+// group holds the nodes sharing a spec.
+type group struct {
+	key     string
+	labels  map[string]string
+	spec    simv1.NodeSimulatorSpec
+	members int
+}
+
+// Generate groups the nodes by allocatable resources, labels, taints and system info,
+// and returns a NodeSimulator per group recreating its nodes, the largest groups first.
+// The allocatable resources become the capacity, since the fake nodes reserve nothing.
+// Fake nodes and the labels and taints of single nodes are left out.
+func Generate(nodes []v1.Node, opts Options) ([]*simv1.NodeSimulator, error) {
+	if opts.Anonymize && opts.Salt == "" {
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		opts.Salt = hex.EncodeToString(salt)
+	}
+	groups := make(map[string]*group)
+	for i := range nodes {
+		node := &nodes[i]
+		if node.GetLabels()[simnode.ManageLabelKey] == simnode.ManageLabelValue {
+			continue
+		}
+		g, err := newGroup(node, opts)
+		if err != nil {
+			return nil, err
+		}
+		if existing, ok := groups[g.key]; ok {
+			existing.members++
+			continue
+		}
+		groups[g.key] = g
+	}
+
+	sorted := make([]*group, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].members != sorted[j].members {
+			return sorted[i].members > sorted[j].members
+		}
+		return sorted[i].key < sorted[j].key
+	})
+
+	prefix := opts.NamePrefix
+	if prefix == "" {
+		prefix = "snapshot"
+	}
+	nodeSims := make([]*simv1.NodeSimulator, 0, len(sorted))
+	for i, g := range sorted {
+		nodeSim := &simv1.NodeSimulator{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: simv1.GroupVersion.String(),
+				Kind:       "NodeSimulator",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      prefix + "-" + strconv.Itoa(i),
+				Namespace: opts.Namespace,
+				Labels:    g.labels,
+			},
+			Spec: g.spec,
+		}
+		nodeSim.Spec.Number = g.members
+		nodeSims = append(nodeSims, nodeSim)
+	}
+	return nodeSims, nil
+}
+
+func newGroup(node *v1.Node, opts Options) (*group, error) {
+	labels := make(map[string]string, len(node.GetLabels()))
+	for key, value := range node.GetLabels() {
+		if nodeLabels[key] {
+			continue
+		}
+		if opts.Anonymize && !isKubernetesKey(key) {
+			key, value = anonymize(opts.Salt, key), anonymize(opts.Salt, value)
+		}
+		labels[key] = value
+	}
+
+	taints := make([]v1.Taint, 0, len(node.Spec.Taints))
+	for _, taint := range node.Spec.Taints {
+		if nodeTaints[taint.Key] {
+			continue
+		}
+		taint.TimeAdded = nil
+		if opts.Anonymize && !isKubernetesKey(taint.Key) {
+			taint.Key, taint.Value = anonymize(opts.Salt, taint.Key), anonymize(opts.Salt, taint.Value)
+		}
+		taints = append(taints, taint)
+	}
+	sort.Slice(taints, func(i, j int) bool {
+		if taints[i].Key != taints[j].Key {
+			return taints[i].Key < taints[j].Key
+		}
+		return taints[i].Effect < taints[j].Effect
+	})
+
+	// Allocatable is what the pods can get, the capacity of a real node also counts
+	// the reservations of the system and the kubelet.
+	resources := node.Status.Allocatable
+	if len(resources) == 0 {
+		resources = node.Status.Capacity
+	}
+	capacity := v1.ResourceList{}
+	for name, quantity := range resources {
+		// Set from the CSI drivers of the NodeSimulator
+		if strings.HasPrefix(string(name), v1.ResourceAttachableVolumesPrefix) {
+			continue
+		}
+		capacity[name] = quantity
+	}
+
+	info := node.Status.NodeInfo
+	spec := simv1.NodeSimulatorSpec{
+		Capacity: capacity,
+		NodeInfo: &simv1.NodeInfoSpec{
+			OperatingSystem:         info.OperatingSystem,
+			Architecture:            info.Architecture,
+			OSImage:                 info.OSImage,
+			KernelVersion:           info.KernelVersion,
+			KubeletVersion:          info.KubeletVersion,
+			KubeProxyVersion:        info.KubeProxyVersion,
+			ContainerRuntimeVersion: info.ContainerRuntimeVersion,
+		},
+	}
+	if len(taints) > 0 {
+		spec.Taints = taints
+	}
+
+	key, err := json.Marshal(struct {
+		Labels map[string]string
+		Spec   simv1.NodeSimulatorSpec
+	}{labels, spec})
+	if err != nil {
+		return nil, err
+	}
+	return &group{key: string(key), labels: labels, spec: spec, members: 1}, nil
+}
+
+// isKubernetesKey reports whether a label or taint key is under the kubernetes.io
+// or k8s.io domains, which are kept by Anonymize with their values.
+func isKubernetesKey(key string) bool {
+	i := strings.Index(key, "/")
+	if i < 0 {
+		return false
+	}
+	domain := key[:i]
+	for _, suffix := range []string{"kubernetes.io", "k8s.io"} {
+		if domain == suffix || strings.HasSuffix(domain, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// anonymize returns the salted hash of a label or taint key or value, which is a
+// valid name and value. Empty values are kept.
+func anonymize(salt, value string) string {
+	if value == "" {
+		return value
+	}
+	mac := hmac.New(sha256.New, []byte(salt))
+	mac.Write([]byte(value))
+	return "x" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// Write writes the NodeSimulators as YAML documents.
+func Write(w io.Writer, nodeSims []*simv1.NodeSimulator) error {
+	for _, nodeSim := range nodeSims {
+		data, err := yaml.Marshal(nodeSim)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+
+	simnode "github.com/NJUPT-ISL/NodeSimulator/pkg/controllers/node"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGenerate(t *testing.T) {
+	newNode := func(name, cpu string, labels map[string]string, taints ...v1.Taint) v1.Node {
+		all := map[string]string{v1.LabelHostname: name}
+		for key, value := range labels {
+			all[key] = value
+		}
+		return v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: all},
+			Spec:       v1.NodeSpec{Taints: taints},
+			Status: v1.NodeStatus{
+				Capacity: v1.ResourceList{
+					v1.ResourceCPU:                           resource.MustParse("8"),
+					"attachable-volumes-csi-ebs.csi.aws.com": resource.MustParse("25"),
+				},
+				Allocatable: v1.ResourceList{
+					v1.ResourceCPU:                           resource.MustParse(cpu),
+					"attachable-volumes-csi-ebs.csi.aws.com": resource.MustParse("25"),
+				},
+				NodeInfo: v1.NodeSystemInfo{KubeletVersion: "v1.20.0"},
+			},
+		}
+	}
+	gpuTaint := v1.Taint{Key: "example.com/gpu", Value: "true", Effect: v1.TaintEffectNoSchedule}
+	lifecycleTaint := v1.Taint{Key: "node.kubernetes.io/not-ready", Effect: v1.TaintEffectNoExecute}
+	nodes := []v1.Node{
+		newNode("a", "7500m", map[string]string{v1.LabelZoneFailureDomain: "z1", "team": "ml"}, gpuTaint),
+		newNode("b", "7500m", map[string]string{v1.LabelZoneFailureDomain: "z1", "team": "ml"}, gpuTaint, lifecycleTaint),
+		newNode("c", "3", map[string]string{v1.LabelZoneFailureDomain: "z1", "team": "ml"}, gpuTaint),
+		newNode("fake", "7500m", map[string]string{simnode.ManageLabelKey: simnode.ManageLabelValue}),
+	}
+
+	tests := []struct {
+		name   string
+		opts   Options
+		groups []int
+		labels map[string]string
+		taints []v1.Taint
+	}{
+		{
+			name:   "grouped by allocatable, largest first",
+			opts:   Options{Namespace: "default"},
+			groups: []int{2, 1},
+			labels: map[string]string{v1.LabelZoneFailureDomain: "z1", "team": "ml"},
+			taints: []v1.Taint{gpuTaint},
+		},
+		{
+			name:   "anonymized keys and values",
+			opts:   Options{Namespace: "default", Anonymize: true, Salt: "salt"},
+			groups: []int{2, 1},
+			labels: map[string]string{
+				v1.LabelZoneFailureDomain: "z1",
+				anonymize("salt", "team"): anonymize("salt", "ml"),
+			},
+			taints: []v1.Taint{{
+				Key:    anonymize("salt", gpuTaint.Key),
+				Value:  anonymize("salt", gpuTaint.Value),
+				Effect: gpuTaint.Effect,
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodeSims, err := Generate(nodes, test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var groups []int
+			for _, nodeSim := range nodeSims {
+				groups = append(groups, nodeSim.Spec.Number)
+				if nodeSim.GetNamespace() != test.opts.Namespace {
+					t.Errorf("expected namespace %v, got %v", test.opts.Namespace, nodeSim.GetNamespace())
+				}
+				if !reflect.DeepEqual(nodeSim.GetLabels(), test.labels) {
+					t.Errorf("expected labels %v, got %v", test.labels, nodeSim.GetLabels())
+				}
+				if !reflect.DeepEqual(nodeSim.Spec.Taints, test.taints) {
+					t.Errorf("expected taints %v, got %v", test.taints, nodeSim.Spec.Taints)
+				}
+				if nodeSim.Spec.NodeInfo.KubeletVersion != "v1.20.0" {
+					t.Errorf("expected the kubelet version of the nodes, got %v", nodeSim.Spec.NodeInfo.KubeletVersion)
+				}
+			}
+			if !reflect.DeepEqual(groups, test.groups) {
+				t.Fatalf("expected groups %v, got %v", test.groups, groups)
+			}
+			expected := v1.ResourceList{v1.ResourceCPU: resource.MustParse("7500m")}
+			if !equality.Semantic.DeepEqual(nodeSims[0].Spec.Capacity, expected) {
+				t.Errorf("expected capacity %v, got %v", expected, nodeSims[0].Spec.Capacity)
+			}
+			if nodeSims[0].GetName() != "snapshot-0" || nodeSims[1].GetName() != "snapshot-1" {
+				t.Errorf("expected names snapshot-0 and snapshot-1, got %v and %v", nodeSims[0].GetName(), nodeSims[1].GetName())
+			}
+		})
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     [2]string
+		expected bool
+	}{
+		{name: "same salt and value", a: [2]string{"s", "ml"}, b: [2]string{"s", "ml"}, expected: true},
+		{name: "other value", a: [2]string{"s", "ml"}, b: [2]string{"s", "web"}},
+		{name: "other salt", a: [2]string{"s", "ml"}, b: [2]string{"t", "ml"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a, b := anonymize(test.a[0], test.a[1]), anonymize(test.b[0], test.b[1])
+			if (a == b) != test.expected {
+				t.Errorf("expected equal %v, got %q and %q", test.expected, a, b)
+			}
+		})
+	}
+	if value := anonymize("s", ""); value != "" {
+		t.Errorf("expected an empty value to stay empty, got %q", value)
+	}
+}
@@ -0,0 +1,116 @@
+package usage
+
+import (
+	"hash/fnv"
+	"math"
+	"time"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+)
+
+// DefaultSinePeriod is the period of a Sine curve without PeriodSeconds, a diurnal cycle.
+const DefaultSinePeriod = 24 * time.Hour
+
+// DefaultMaxPercent bounds Sine and RandomWalk curves without MaxPercent.
+const DefaultMaxPercent = 100
+
+// ProfileModel computes the usage from a UsageProfileSpec. Resources without a
+// curve in the profile use Fallback.
+type ProfileModel struct {
+	Spec     simv1.UsageProfileSpec
+	Fallback Model
+}
+
+func (m *ProfileModel) ContainerUsage(pod *v1.Pod, container *v1.Container, now time.Time) v1.ResourceList {
+	usage := m.Fallback.ContainerUsage(pod, container, now)
+	elapsed := now.Sub(podStartTime(pod, now))
+	seed := string(pod.GetUID()) + "/" + container.Name
+
+	if m.Spec.CPU != nil {
+		fraction := CurveFraction(m.Spec.CPU, elapsed, seed+"/cpu")
+		usage[v1.ResourceCPU] = Scale(baseQuantity(container, v1.ResourceCPU), fraction)
+	}
+	if m.Spec.Memory != nil {
+		fraction := CurveFraction(m.Spec.Memory, elapsed, seed+"/memory")
+		usage[v1.ResourceMemory] = Scale(baseQuantity(container, v1.ResourceMemory), fraction)
+	}
+	return usage
+}
+
+// CurveFraction returns the fraction of the requests used elapsed after the pod
+// started. seed makes RandomWalk curves differ between containers while staying
+// reproducible for a given container.
+func CurveFraction(curve *simv1.UsageCurve, elapsed time.Duration, seed string) float64 {
+	percent := float64(curve.Percent)
+
+	switch curve.Type {
+	case simv1.UsageLinearRamp:
+		duration := time.Duration(curve.DurationSeconds) * time.Second
+		progress := 1.0
+		if duration > 0 && elapsed < duration {
+			progress = float64(elapsed) / float64(duration)
+		}
+		percent = float64(curve.FromPercent) + float64(curve.ToPercent-curve.FromPercent)*progress
+	case simv1.UsageSine:
+		period := time.Duration(curve.PeriodSeconds) * time.Second
+		if period <= 0 {
+			period = DefaultSinePeriod
+		}
+		percent = SineFraction(float64(curve.MinPercent), maxPercent(curve), period, elapsed)
+	case simv1.UsageRandomWalk:
+		percent = randomWalk(curve, elapsed, seed)
+	case simv1.UsageStep:
+		percent = 0
+		for _, step := range curve.Steps {
+			if time.Duration(step.AfterSeconds)*time.Second > elapsed {
+				break
+			}
+			percent = float64(step.Percent)
+		}
+	}
+	return percent / 100
+}
+
+// RandomWalkWindow is the number of intervals whose moves make up a RandomWalk
+// sample, which bounds its cost however long the pod has been running.
+const RandomWalkWindow = 64
+
+// randomWalk moves from Percent by at most StepPercent every IntervalSeconds,
+// staying within MinPercent and MaxPercent. The sample is Percent plus the moves of
+// the last RandomWalkWindow intervals, each of at most half of StepPercent and
+// derived from the seed and the index of the interval, so that it is computed
+// without replaying the walk since the pod started.
+func randomWalk(curve *simv1.UsageCurve, elapsed time.Duration, seed string) float64 {
+	percent := float64(curve.Percent)
+	if curve.IntervalSeconds <= 0 {
+		return percent
+	}
+
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(seed))
+	base := hash.Sum64()
+
+	steps := int64(elapsed / (time.Duration(curve.IntervalSeconds) * time.Second))
+	first := steps - RandomWalkWindow
+	if first < 0 {
+		first = 0
+	}
+	for i := first; i < steps; i++ {
+		// splitmix64 of the i-th state
+		z := base + uint64(i+1)*0x9e3779b97f4a7c15
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		z ^= z >> 31
+		percent += (float64(z)/math.MaxUint64*2 - 1) * float64(curve.StepPercent) / 2
+	}
+	return math.Max(float64(curve.MinPercent), math.Min(maxPercent(curve), percent))
+}
+
+// maxPercent returns the MaxPercent of the curve, DefaultMaxPercent when unset.
+func maxPercent(curve *simv1.UsageCurve) float64 {
+	if curve.MaxPercent <= 0 {
+		return DefaultMaxPercent
+	}
+	return float64(curve.MaxPercent)
+}
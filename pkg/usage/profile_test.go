@@ -0,0 +1,128 @@
+package usage
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+)
+
+func TestSineFraction(t *testing.T) {
+	tests := []struct {
+		name     string
+		period   time.Duration
+		elapsed  time.Duration
+		expected float64
+	}{
+		{name: "start", period: time.Hour, elapsed: 0, expected: 0.5},
+		{name: "peak", period: time.Hour, elapsed: 15 * time.Minute, expected: 0.8},
+		{name: "trough", period: time.Hour, elapsed: 45 * time.Minute, expected: 0.2},
+		{name: "next period", period: time.Hour, elapsed: 75 * time.Minute, expected: 0.8},
+		{name: "no period", elapsed: 15 * time.Minute, expected: 0.2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if fraction := SineFraction(0.2, 0.8, test.period, test.elapsed); math.Abs(fraction-test.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", test.expected, fraction)
+			}
+		})
+	}
+}
+
+func TestCurveFraction(t *testing.T) {
+	ramp := &simv1.UsageCurve{Type: simv1.UsageLinearRamp, FromPercent: 50, ToPercent: 150, DurationSeconds: 600}
+	sine := &simv1.UsageCurve{Type: simv1.UsageSine, MinPercent: 20, MaxPercent: 80, PeriodSeconds: 3600}
+	step := &simv1.UsageCurve{Type: simv1.UsageStep, Steps: []simv1.UsageCurveStep{
+		{AfterSeconds: 60, Percent: 40},
+		{AfterSeconds: 120, Percent: 90},
+	}}
+
+	tests := []struct {
+		name     string
+		curve    *simv1.UsageCurve
+		elapsed  time.Duration
+		expected float64
+	}{
+		{name: "constant", curve: &simv1.UsageCurve{Type: simv1.UsageConstant, Percent: 30}, expected: 0.3},
+		{name: "ramp start", curve: ramp, expected: 0.5},
+		{name: "ramp halfway", curve: ramp, elapsed: 5 * time.Minute, expected: 1},
+		{name: "ramp end", curve: ramp, elapsed: time.Hour, expected: 1.5},
+		{name: "ramp without duration", curve: &simv1.UsageCurve{Type: simv1.UsageLinearRamp, FromPercent: 50, ToPercent: 150}, expected: 1.5},
+		{name: "sine peak", curve: sine, elapsed: 15 * time.Minute, expected: 0.8},
+		{name: "sine trough", curve: sine, elapsed: 45 * time.Minute, expected: 0.2},
+		{name: "sine peak without maxPercent", curve: &simv1.UsageCurve{Type: simv1.UsageSine, PeriodSeconds: 3600}, elapsed: 15 * time.Minute, expected: 1},
+		{name: "sine daily period by default", curve: &simv1.UsageCurve{Type: simv1.UsageSine, MaxPercent: 80}, elapsed: 6 * time.Hour, expected: 0.8},
+		{name: "before the first step", curve: step, elapsed: 30 * time.Second, expected: 0},
+		{name: "first step", curve: step, elapsed: time.Minute, expected: 0.4},
+		{name: "last step", curve: step, elapsed: time.Hour, expected: 0.9},
+		{name: "random walk without interval", curve: &simv1.UsageCurve{Type: simv1.UsageRandomWalk, Percent: 50, StepPercent: 10}, elapsed: time.Hour, expected: 0.5},
+		{name: "random walk before the first move", curve: &simv1.UsageCurve{Type: simv1.UsageRandomWalk, Percent: 50, StepPercent: 10, IntervalSeconds: 60}, elapsed: 30 * time.Second, expected: 0.5},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if fraction := CurveFraction(test.curve, test.elapsed, "seed"); math.Abs(fraction-test.expected) > 1e-9 {
+				t.Errorf("expected %v, got %v", test.expected, fraction)
+			}
+		})
+	}
+}
+
+func TestRandomWalk(t *testing.T) {
+	tests := []struct {
+		name     string
+		curve    *simv1.UsageCurve
+		min, max float64
+	}{
+		{
+			name:  "within the bounds",
+			curve: &simv1.UsageCurve{Percent: 50, MinPercent: 45, MaxPercent: 55, StepPercent: 10, IntervalSeconds: 10},
+			min:   45,
+			max:   55,
+		},
+		{
+			name:  "100 without maxPercent",
+			curve: &simv1.UsageCurve{Percent: 95, StepPercent: 20, IntervalSeconds: 10},
+			min:   0,
+			max:   100,
+		},
+		{
+			name:  "within the window around percent",
+			curve: &simv1.UsageCurve{Percent: 50, StepPercent: 2, IntervalSeconds: 10},
+			min:   50 - RandomWalkWindow,
+			max:   50 + RandomWalkWindow,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			moved := false
+			previous := randomWalk(test.curve, 0, "seed")
+			for elapsed := time.Duration(0); elapsed < 24*time.Hour; elapsed += time.Minute {
+				percent := randomWalk(test.curve, elapsed, "seed")
+				if percent < test.min || percent > test.max {
+					t.Fatalf("expected within [%v, %v] at %v, got %v", test.min, test.max, elapsed, percent)
+				}
+				if percent != randomWalk(test.curve, elapsed, "seed") {
+					t.Fatalf("expected the same sample at %v", elapsed)
+				}
+				// Consecutive intervals differ by at most StepPercent
+				next := randomWalk(test.curve, elapsed+time.Duration(test.curve.IntervalSeconds)*time.Second, "seed")
+				if math.Abs(next-percent) > float64(test.curve.StepPercent)+1e-9 {
+					t.Fatalf("expected a move of at most %d at %v, got %v", test.curve.StepPercent, elapsed, next-percent)
+				}
+				moved = moved || percent != previous
+			}
+			if !moved {
+				t.Errorf("expected the walk to move")
+			}
+		})
+	}
+
+	curve := &simv1.UsageCurve{Percent: 50, StepPercent: 10, IntervalSeconds: 10}
+	if randomWalk(curve, time.Hour, "a") == randomWalk(curve, time.Hour, "b") {
+		t.Errorf("expected seeds to walk apart")
+	}
+}
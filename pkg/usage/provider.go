@@ -0,0 +1,84 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	simv1 "github.com/NJUPT-ISL/NodeSimulator/pkg/api/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// UsageAnnotationKey holds an inline UsageProfileSpec in JSON.
+	UsageAnnotationKey = "sim.k8s.io/usage"
+	// UsageProfileAnnotationKey names a UsageProfile in the namespace of the pod.
+	UsageProfileAnnotationKey = "sim.k8s.io/usage-profile"
+)
+
+// Provider returns the current simulated usage of pods. The node and pod
+// controllers and the metrics API share it, so that node pressure, OOM kills and
+// the reported metrics all agree.
+type Provider interface {
+	ContainerUsage(ctx context.Context, pod *v1.Pod, container *v1.Container, now time.Time) v1.ResourceList
+	PodUsage(ctx context.Context, pod *v1.Pod, now time.Time) v1.ResourceList
+}
+
+// ProfileProvider picks the model of each pod from its annotations, and falls
+// back to Default for pods without a usage profile.
+type ProfileProvider struct {
+	Client  client.Client
+	Default Model
+}
+
+func NewProfileProvider(c client.Client, defaultModel Model) *ProfileProvider {
+	return &ProfileProvider{
+		Client:  c,
+		Default: defaultModel,
+	}
+}
+
+func (p *ProfileProvider) ContainerUsage(ctx context.Context, pod *v1.Pod, container *v1.Container, now time.Time) v1.ResourceList {
+	return p.modelFor(ctx, pod).ContainerUsage(pod, container, now)
+}
+
+func (p *ProfileProvider) PodUsage(ctx context.Context, pod *v1.Pod, now time.Time) v1.ResourceList {
+	return PodUsage(p.modelFor(ctx, pod), pod, now)
+}
+
+func (p *ProfileProvider) modelFor(ctx context.Context, pod *v1.Pod) Model {
+	annotations := pod.GetAnnotations()
+
+	if value, ok := annotations[UsageAnnotationKey]; ok {
+		spec := simv1.UsageProfileSpec{}
+		if err := json.Unmarshal([]byte(value), &spec); err != nil {
+			klog.Errorf("Pod: %v/%v Parse Annotation %v Error: %v", pod.GetNamespace(), pod.GetName(), UsageAnnotationKey, err)
+			return p.Default
+		}
+		return &ProfileModel{Spec: spec, Fallback: p.Default}
+	}
+
+	if name, ok := annotations[UsageProfileAnnotationKey]; ok {
+		profile := &simv1.UsageProfile{}
+		err := p.Client.Get(ctx, types.NamespacedName{Namespace: pod.GetNamespace(), Name: name}, profile)
+		if err != nil {
+			klog.Errorf("Pod: %v/%v Get UsageProfile: %v Error: %v", pod.GetNamespace(), pod.GetName(), name, err)
+			return p.Default
+		}
+		return &ProfileModel{Spec: profile.Spec, Fallback: p.Default}
+	}
+
+	return p.Default
+}
+
+// NodeUsage sums the usage of the pods running on a node.
+func NodeUsage(ctx context.Context, provider Provider, pods []v1.Pod, now time.Time) v1.ResourceList {
+	total := v1.ResourceList{}
+	for i := range pods {
+		AddResourceList(total, provider.PodUsage(ctx, &pods[i], now))
+	}
+	return total
+}
@@ -0,0 +1,200 @@
+package usage
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Model computes the simulated resource usage of a container at a point in time.
+type Model interface {
+	ContainerUsage(pod *v1.Pod, container *v1.Container, now time.Time) v1.ResourceList
+}
+
+// FractionFunc returns the fraction of the requests the cpu and the memory use.
+type FractionFunc func(pod *v1.Pod, now time.Time) (cpu float64, memory float64)
+
+// ScaledModel scales the requests of a container, or its limits when it has no
+// requests, by the fractions returned by Fraction.
+type ScaledModel struct {
+	Fraction FractionFunc
+}
+
+func (m *ScaledModel) ContainerUsage(pod *v1.Pod, container *v1.Container, now time.Time) v1.ResourceList {
+	cpuFraction, memoryFraction := m.Fraction(pod, now)
+	return v1.ResourceList{
+		v1.ResourceCPU:    Scale(baseQuantity(container, v1.ResourceCPU), cpuFraction),
+		v1.ResourceMemory: Scale(baseQuantity(container, v1.ResourceMemory), memoryFraction),
+	}
+}
+
+func baseQuantity(container *v1.Container, name v1.ResourceName) resource.Quantity {
+	if value, ok := container.Resources.Requests[name]; ok {
+		return value
+	}
+	if value, ok := container.Resources.Limits[name]; ok {
+		return value
+	}
+	return resource.Quantity{}
+}
+
+// Scale multiplies a quantity by fraction, keeping milli precision.
+func Scale(quantity resource.Quantity, fraction float64) resource.Quantity {
+	if fraction < 0 {
+		fraction = 0
+	}
+	format := quantity.Format
+	if format == "" {
+		format = resource.DecimalSI
+	}
+	return *resource.NewMilliQuantity(int64(float64(quantity.MilliValue())*fraction), format)
+}
+
+// NewFractionModel returns a model using a constant fraction of the requests.
+func NewFractionModel(fraction float64) Model {
+	return &ScaledModel{Fraction: func(*v1.Pod, time.Time) (float64, float64) {
+		return fraction, fraction
+	}}
+}
+
+// NewSineModel returns a model oscillating between min and max fractions of the
+// requests over period. Pods are shifted by their start time so they don't peak together.
+func NewSineModel(min, max float64, period time.Duration) Model {
+	return &ScaledModel{Fraction: func(pod *v1.Pod, now time.Time) (float64, float64) {
+		fraction := SineFraction(min, max, period, now.Sub(podStartTime(pod, now)))
+		return fraction, fraction
+	}}
+}
+
+// SineFraction is the value of a sine wave between min and max at elapsed.
+func SineFraction(min, max float64, period time.Duration, elapsed time.Duration) float64 {
+	if period <= 0 {
+		return min
+	}
+	phase := 2 * math.Pi * float64(elapsed) / float64(period)
+	return min + (max-min)*(1+math.Sin(phase))/2
+}
+
+// TracePoint is the usage fractions from Offset on.
+type TracePoint struct {
+	Offset time.Duration
+	CPU    float64
+	Memory float64
+}
+
+// NewTraceModel returns a model replaying points, relative to the start time of
+// each pod, and looping over the trace once it ends.
+func NewTraceModel(points []TracePoint) (Model, error) {
+	if len(points) == 0 {
+		return nil, errors.New("usage trace is empty")
+	}
+	length := points[len(points)-1].Offset
+	return &ScaledModel{Fraction: func(pod *v1.Pod, now time.Time) (float64, float64) {
+		elapsed := now.Sub(podStartTime(pod, now))
+		if length > 0 {
+			elapsed = elapsed % (length + time.Second)
+		}
+		point := points[0]
+		for _, p := range points {
+			if p.Offset > elapsed {
+				break
+			}
+			point = p
+		}
+		return point.CPU, point.Memory
+	}}, nil
+}
+
+// LoadTrace reads a CSV trace with "seconds,cpuFraction,memoryFraction" rows
+// sorted by seconds.
+func LoadTrace(path string) ([]TracePoint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 3
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]TracePoint, 0, len(records))
+	for i, record := range records {
+		values := make([]float64, len(record))
+		for j := range record {
+			values[j], err = strconv.ParseFloat(record[j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("usage trace %v line %v: %v", path, i+1, err)
+			}
+		}
+		points = append(points, TracePoint{
+			Offset: time.Duration(values[0] * float64(time.Second)),
+			CPU:    values[1],
+			Memory: values[2],
+		})
+	}
+	return points, nil
+}
+
+func podStartTime(pod *v1.Pod, now time.Time) time.Time {
+	if pod.Status.StartTime != nil {
+		return pod.Status.StartTime.Time
+	}
+	return now
+}
+
+// PodUsage sums the usage of all the containers of a pod.
+func PodUsage(model Model, pod *v1.Pod, now time.Time) v1.ResourceList {
+	total := v1.ResourceList{}
+	for i := range pod.Spec.Containers {
+		AddResourceList(total, model.ContainerUsage(pod, &pod.Spec.Containers[i], now))
+	}
+	return total
+}
+
+// AddResourceList adds the quantities of list to total.
+func AddResourceList(total, list v1.ResourceList) {
+	for name, value := range list {
+		quantity := total[name]
+		quantity.Add(value)
+		total[name] = quantity
+	}
+}
+
+// Options selects and configures the usage model from command line flags.
+type Options struct {
+	// Model is one of fraction, sine or trace.
+	Model     string
+	Fraction  float64
+	Min       float64
+	Max       float64
+	Period    time.Duration
+	TracePath string
+}
+
+func (o *Options) NewModel() (Model, error) {
+	switch o.Model {
+	case "fraction":
+		return NewFractionModel(o.Fraction), nil
+	case "sine":
+		return NewSineModel(o.Min, o.Max, o.Period), nil
+	case "trace":
+		points, err := LoadTrace(o.TracePath)
+		if err != nil {
+			return nil, err
+		}
+		return NewTraceModel(points)
+	}
+	return nil, fmt.Errorf("unknown usage model %q", o.Model)
+}
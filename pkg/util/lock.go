@@ -0,0 +1,40 @@
+package util
+
+import "sync"
+
+// KeyedMutex is a set of mutexes, one per key. The zero value is ready to use.
+type KeyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// Lock locks the mutex of key and returns the function unlocking it.
+func (m *KeyedMutex) Lock(key string) func() {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[string]*keyedLock)
+	}
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		m.locks[key] = lock
+	}
+	lock.waiters++
+	m.mu.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		m.mu.Lock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}
+}
@@ -0,0 +1,15 @@
+package util
+
+// WatchesNamespace reports whether namespace is one of namespaces, which holds all
+// of them when empty.
+func WatchesNamespace(namespaces []string, namespace string) bool {
+	if len(namespaces) == 0 {
+		return true
+	}
+	for _, watched := range namespaces {
+		if watched == namespace {
+			return true
+		}
+	}
+	return false
+}
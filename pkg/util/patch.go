@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	"strings"
 )
 
 type Ops struct {
@@ -29,6 +30,11 @@ func (p *Patch) Data(obj runtime.Object) ([]byte, error) {
 	return json.Marshal(p.PatchOps)
 }
 
+// EscapeJSONPointer escapes a map key, such as a label or annotation key, for use in an Ops path.
+func EscapeJSONPointer(key string) string {
+	return strings.Replace(strings.Replace(key, "~", "~0", -1), "/", "~1", -1)
+}
+
 // PatchNodeStatus patches node status.
 func PatchNodeStatus(c v1core.CoreV1Interface, nodeName types.NodeName, oldNode *v1.Node, newNode *v1.Node) (*v1.Node, []byte, error) {
 	patchBytes, err := preparePatchBytesforNodeStatus(nodeName, oldNode, newNode)
@@ -0,0 +1,47 @@
+package util
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// RateLimitedRecorder drops events once the shared token bucket is empty, so a large
+// fleet of simulated objects cannot flood the API server with events. The per-object
+// spam filter of the broadcaster still applies on top of it.
+type RateLimitedRecorder struct {
+	Recorder record.EventRecorder
+	Limiter  flowcontrol.RateLimiter
+}
+
+func NewRateLimitedRecorder(recorder record.EventRecorder, qps float32, burst int) *RateLimitedRecorder {
+	return &RateLimitedRecorder{
+		Recorder: recorder,
+		Limiter:  flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+	}
+}
+
+func (r *RateLimitedRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	if r.Limiter.TryAccept() {
+		r.Recorder.Event(object, eventtype, reason, message)
+	}
+}
+
+func (r *RateLimitedRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.Limiter.TryAccept() {
+		r.Recorder.Eventf(object, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (r *RateLimitedRecorder) PastEventf(object runtime.Object, timestamp metav1.Time, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.Limiter.TryAccept() {
+		r.Recorder.PastEventf(object, timestamp, eventtype, reason, messageFmt, args...)
+	}
+}
+
+func (r *RateLimitedRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.Limiter.TryAccept() {
+		r.Recorder.AnnotatedEventf(object, annotations, eventtype, reason, messageFmt, args...)
+	}
+}
@@ -0,0 +1,48 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+func TestRateLimitedRecorder(t *testing.T) {
+	fakeClock := clock.NewFakeClock(time.Now())
+	fakeRecorder := record.NewFakeRecorder(100)
+	recorder := &RateLimitedRecorder{
+		Recorder: fakeRecorder,
+		Limiter:  flowcontrol.NewTokenBucketRateLimiterWithClock(1, 3, fakeClock),
+	}
+	pod := &v1.Pod{}
+
+	tests := []struct {
+		name     string
+		wait     time.Duration
+		events   int
+		expected int
+	}{
+		{name: "burst admitted", events: 3, expected: 3},
+		{name: "past the burst dropped", events: 2, expected: 0},
+		{name: "admitted again once refilled", wait: 2 * time.Second, events: 3, expected: 2},
+		{name: "admitted at the rate", wait: time.Second, events: 1, expected: 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClock.Step(test.wait)
+			for i := 0; i < test.events; i++ {
+				recorder.Eventf(pod, v1.EventTypeNormal, "Started", "Started container %d", i)
+			}
+			if recorded := len(fakeRecorder.Events); recorded != test.expected {
+				t.Errorf("expected %d events, got %d", test.expected, recorded)
+			}
+			for len(fakeRecorder.Events) > 0 {
+				<-fakeRecorder.Events
+			}
+		})
+	}
+}